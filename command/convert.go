@@ -1,7 +1,6 @@
 package command
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -16,20 +15,30 @@ import (
 
 // ConvertCommandArguments represent available command arguments for the convert command
 type ConvertCommandArguments struct {
-	File                string            `help:"File path needs to be converted." short:"f"`
-	Config              string            `help:"Path of the config file." short:"c"`
-	Output              string            `help:"The location where the ndc schema file will be generated. Print to stdout if not set" short:"o"`
-	Spec                string            `help:"The API specification of the file, is one of oas3 (openapi3), oas2 (openapi2)"`
-	Format              string            `help:"The output format, is one of json, yaml. If the output is set, automatically detect the format in the output file extension" default:"json"`
-	Strict              bool              `help:"Require strict validation" default:"false"`
-	Pure                bool              `help:"Return the pure NDC schema only" default:"false"`
-	Prefix              string            `help:"Add a prefix to the function and procedure names"`
-	TrimPrefix          string            `help:"Trim the prefix in URL, e.g. /v1"`
-	EnvPrefix           string            `help:"The environment variable prefix for security values, e.g. PET_STORE"`
-	MethodAlias         map[string]string `help:"Alias names for HTTP method. Used for prefix renaming, e.g. getUsers, postUser"`
-	AllowedContentTypes []string          `help:"Allowed content types. All content types are allowed by default"`
-	PatchBefore         []string          `help:"Patch files to be applied into the input file before converting"`
-	PatchAfter          []string          `help:"Patch files to be applied into the input file after converting"`
+	File                  string            `help:"File path needs to be converted." short:"f"`
+	Config                string            `help:"Path of the config file." short:"c"`
+	Output                string            `help:"The location where the ndc schema file will be generated. Print to stdout if not set" short:"o"`
+	Spec                  string            `help:"The API specification of the file, is one of oas3 (openapi3), oas2 (openapi2), ndc. Automatically detect the spec from the document if not set"`
+	Format                string            `help:"The output format, is one of json, yaml. If the output is set, automatically detect the format in the output file extension" default:"json"`
+	Strict                bool              `help:"Require strict validation" default:"false"`
+	Tolerant              bool              `help:"Skip operations that fail to be converted and report all errors instead of stopping at the first error" default:"false"`
+	DetectCollections     bool              `help:"Detect paginated GET list endpoints and convert them to collections" default:"false"`
+	ResponseHeaders       bool              `help:"Wrap results in envelope objects with the headers and body fields if the success response documents headers" default:"false"`
+	StatusCodeResponses   bool              `help:"Record responses of all documented status codes and merge success responses with different types into union objects" default:"false"`
+	TypedMaps             bool              `help:"Convert objects with typed additionalProperties to arrays of key-value entry objects instead of JSON scalars" default:"false"`
+	Int64Representation   string            `help:"The JSON representation of integers with int64 and uint64 formats, is one of number, string, bigdecimal. Inferred from the schema type if not set"`
+	DecimalRepresentation string            `help:"The JSON representation of numbers with the decimal format, is one of number, string, bigdecimal. Inferred from the schema type if not set"`
+	Pure                  bool              `help:"Return the pure NDC schema only" default:"false"`
+	Prefix                string            `help:"Add a prefix to the function and procedure names"`
+	TrimPrefix            string            `help:"Trim the prefix in URL, e.g. /v1"`
+	EnvPrefix             string            `help:"The environment variable prefix for security values, e.g. PET_STORE"`
+	MethodAlias           map[string]string `help:"Alias names for HTTP method. Used for prefix renaming, e.g. getUsers, postUser"`
+	AllowedContentTypes   []string          `help:"Allowed content types. All content types are allowed by default"`
+	ExtraMethods          []string          `help:"Convert operations of extra HTTP methods: head, options, trace. These operations are skipped by default"`
+	PatchBefore           []string          `help:"Patch files to be applied into the input file before converting"`
+	PatchAfter            []string          `help:"Patch files to be applied into the input file after converting"`
+	Report                string            `help:"The location where the conversion report of skipped operations, JSON fallbacks and name collisions will be written. Print a summary table to stderr if not set"`
+	SourceMap             string            `help:"The location where the source map of functions, procedures and types will be written. The source map isn't generated if not set"`
 }
 
 // ConvertToNDCSchema converts to NDC REST schema from file
@@ -46,8 +55,18 @@ func CommandConvertToNDCSchema(args *ConvertCommandArguments, logger *slog.Logge
 		slog.String("env_prefix", args.EnvPrefix),
 		slog.Any("patch_before", args.PatchBefore),
 		slog.Any("patch_after", args.PatchAfter),
+		slog.String("report", args.Report),
+		slog.String("source_map", args.SourceMap),
+		slog.String("int64_representation", args.Int64Representation),
+		slog.String("decimal_representation", args.DecimalRepresentation),
 		slog.Any("allowed_content_types", args.AllowedContentTypes),
+		slog.Any("extra_methods", args.ExtraMethods),
 		slog.Bool("strict", args.Strict),
+		slog.Bool("tolerant", args.Tolerant),
+		slog.Bool("detect_collections", args.DetectCollections),
+		slog.Bool("response_headers", args.ResponseHeaders),
+		slog.Bool("status_code_responses", args.StatusCodeResponses),
+		slog.Bool("typed_maps", args.TypedMaps),
 		slog.Bool("pure", args.Pure),
 	)
 
@@ -78,13 +97,30 @@ func CommandConvertToNDCSchema(args *ConvertCommandArguments, logger *slog.Logge
 	}
 
 	ResolveConvertConfigArguments(&config, configDir, args)
-	result, err := ConvertToNDCSchema(&config, logger)
+	result, report, err := ConvertToNDCSchemaWithReport(&config, logger)
 
 	if err != nil {
 		logger.Error(err.Error())
 		return err
 	}
 
+	if report != nil {
+		if config.Report != "" {
+			if err := utils.WriteSchemaFile(config.Report, report); err != nil {
+				logger.Error("failed to write the conversion report", slog.String("error", err.Error()))
+				return err
+			}
+		} else if !report.IsEmpty() {
+			_, _ = fmt.Fprint(os.Stderr, report.String())
+		}
+		if config.SourceMap != "" && report.SourceMap != nil {
+			if err := utils.WriteSchemaFile(config.SourceMap, report.SourceMap); err != nil {
+				logger.Error("failed to write the source map", slog.String("error", err.Error()))
+				return err
+			}
+		}
+	}
+
 	if config.Output != "" {
 		if config.Pure {
 			err = utils.WriteSchemaFile(config.Output, result.ToSchemaResponse())
@@ -129,8 +165,9 @@ func CommandConvertToNDCSchema(args *ConvertCommandArguments, logger *slog.Logge
 type ConvertConfig struct {
 	// File path needs to be converted
 	File string `json:"file" yaml:"file" jsonschema:"required"`
-	// The API specification of the file, is one of oas3 (openapi3), oas2 (openapi2)
-	Spec schema.SchemaSpecType `json:"spec,omitempty" yaml:"spec" jsonschema:"default=oas3"`
+	// The API specification of the file, is one of oas3 (openapi3), oas2 (openapi2), ndc.
+	// Automatically detect the spec from the document if not set
+	Spec schema.SchemaSpecType `json:"spec,omitempty" yaml:"spec"`
 	// Alias names for HTTP method. Used for prefix renaming, e.g. getUsers, postUser
 	MethodAlias map[string]string `json:"methodAlias,omitempty" yaml:"methodAlias"`
 	// Add a prefix to the function and procedure names
@@ -143,60 +180,130 @@ type ConvertConfig struct {
 	Pure bool `json:"pure,omitempty" yaml:"pure"`
 	// Require strict validation
 	Strict bool `json:"strict,omitempty" yaml:"strict"`
+	// Skip operations that fail to be converted and report all errors instead of stopping at the first error
+	Tolerant bool `json:"tolerant,omitempty" yaml:"tolerant"`
 	// Patch files to be applied into the input file before converting
 	PatchBefore []utils.PatchConfig `json:"patchBefore,omitempty" yaml:"patchBefore"`
 	// Patch files to be applied into the input file after converting
 	PatchAfter []utils.PatchConfig `json:"patchAfter,omitempty" yaml:"patchAfter"`
 	// Allowed content types. All content types are allowed by default
 	AllowedContentTypes []string `json:"allowedContentTypes,omitempty" yaml:"allowedContentTypes"`
+	// Include operations which match any of these rules. All operations are included if empty
+	Include []utils.OperationFilter `json:"include,omitempty" yaml:"include"`
+	// Exclude operations which match any of these rules
+	Exclude []utils.OperationFilter `json:"exclude,omitempty" yaml:"exclude"`
+	// Wrap results in envelope objects with the headers and body fields if the success response documents headers
+	ResponseHeaders bool `json:"responseHeaders,omitempty" yaml:"responseHeaders"`
+	// Record responses of all documented status codes. Success responses with different types are merged into union objects
+	// and error payloads are converted to object types
+	StatusCodeResponses bool `json:"statusCodeResponses,omitempty" yaml:"statusCodeResponses"`
+	// Convert operations of extra HTTP methods: head, options and trace. These operations are skipped by default
+	ExtraMethods []string `json:"extraMethods,omitempty" yaml:"extraMethods" jsonschema:"enum=head,enum=options,enum=trace"`
+	// Override the NDC operation kind of operations which match these rules, e.g. convert POST search endpoints to functions.
+	// Rules take precedence over the x-ndc-operation extension of the document
+	OperationKinds []utils.OperationKindRule `json:"operationKinds,omitempty" yaml:"operationKinds"`
+	// Map OpenAPI schemas which match the type, format or extension to custom scalar types. The first matched mapping wins
+	ScalarMappings []utils.ScalarMapping `json:"scalarMappings,omitempty" yaml:"scalarMappings"`
+	// Convert objects with typed additionalProperties and no fixed properties to arrays of key-value entry objects instead of JSON scalars
+	TypedMaps bool `json:"typedMaps,omitempty" yaml:"typedMaps"`
+	// The JSON representation of integers with int64 and uint64 formats. Inferred from the schema type if empty
+	Int64Representation schema.NumberRepresentation `json:"int64Representation,omitempty" yaml:"int64Representation"`
+	// The JSON representation of numbers with the decimal format. Inferred from the schema type if empty
+	DecimalRepresentation schema.NumberRepresentation `json:"decimalRepresentation,omitempty" yaml:"decimalRepresentation"`
+	// Detect paginated GET list endpoints and convert them to collections
+	DetectCollections bool `json:"detectCollections,omitempty" yaml:"detectCollections"`
+	// Convert GET list endpoints which match any of these rules to collections
+	Collections []utils.CollectionRule `json:"collections,omitempty" yaml:"collections"`
 	// The location where the ndc schema file will be generated. Print to stdout if not set
 	Output string `json:"output,omitempty" yaml:"output"`
+	// The location where the conversion report of skipped operations, JSON fallbacks and name collisions will be written.
+	// Print a summary table to stderr if not set
+	Report string `json:"report,omitempty" yaml:"report"`
+	// The location where the source map of functions, procedures and types will be written.
+	// The source map isn't generated if not set
+	SourceMap string `json:"sourceMap,omitempty" yaml:"sourceMap"`
 }
 
 // ConvertToNDCSchema converts to NDC REST schema from config
 func ConvertToNDCSchema(config *ConvertConfig, logger *slog.Logger) (*schema.NDCRestSchema, error) {
+	result, _, err := ConvertToNDCSchemaWithReport(config, logger)
+	return result, err
+}
+
+// ConvertToNDCSchemaWithReport converts to NDC REST schema from config and returns the conversion report.
+// The report is nil if the input is a NDC REST schema
+func ConvertToNDCSchemaWithReport(config *ConvertConfig, logger *slog.Logger) (*schema.NDCRestSchema, *openapi.ConversionReport, error) {
 
 	rawContent, err := utils.ReadFileFromPath(config.File)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rawContent, err = utils.ApplyPatch(rawContent, config.PatchBefore)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	spec := config.Spec
+	if spec == "" {
+		spec, err = utils.DetectSchemaSpec(rawContent)
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.Debug("detected the spec of the document", slog.String("spec", string(spec)))
 	}
 
 	var result *schema.NDCRestSchema
+	var report *openapi.ConversionReport
 	var errs []error
 	options := openapi.ConvertOptions{
-		MethodAlias:         config.MethodAlias,
-		Prefix:              config.Prefix,
-		TrimPrefix:          config.TrimPrefix,
-		EnvPrefix:           config.EnvPrefix,
-		AllowedContentTypes: config.AllowedContentTypes,
-		Strict:              config.Strict,
-		Logger:              logger,
+		MethodAlias:           config.MethodAlias,
+		Prefix:                config.Prefix,
+		TrimPrefix:            config.TrimPrefix,
+		EnvPrefix:             config.EnvPrefix,
+		AllowedContentTypes:   config.AllowedContentTypes,
+		Strict:                config.Strict,
+		Tolerant:              config.Tolerant,
+		SourceMap:             config.SourceMap != "",
+		Include:               config.Include,
+		Exclude:               config.Exclude,
+		ResponseHeaders:       config.ResponseHeaders,
+		StatusCodeResponses:   config.StatusCodeResponses,
+		ExtraMethods:          config.ExtraMethods,
+		OperationKinds:        config.OperationKinds,
+		ScalarMappings:        config.ScalarMappings,
+		TypedMaps:             config.TypedMaps,
+		Int64Representation:   config.Int64Representation,
+		DecimalRepresentation: config.DecimalRepresentation,
+		DetectCollections:     config.DetectCollections,
+		Collections:           config.Collections,
+		Logger:                logger,
 	}
-	switch config.Spec {
+	switch spec {
 	case schema.OpenAPIv3Spec, schema.OAS3Spec:
-		result, errs = openapi.OpenAPIv3ToNDCSchema(rawContent, options)
+		result, report, errs = openapi.OpenAPIv3ToNDCSchemaWithReport(rawContent, options)
 	case schema.OpenAPIv2Spec, (schema.OAS2Spec):
-		result, errs = openapi.OpenAPIv2ToNDCSchema(rawContent, options)
+		result, report, errs = openapi.OpenAPIv2ToNDCSchemaWithReport(rawContent, options)
 	case schema.NDCSpec:
-		if err := json.Unmarshal(rawContent, &result); err != nil {
-			return nil, err
+		result, err = utils.UnmarshalSchema(rawContent)
+		if err != nil {
+			return nil, nil, err
 		}
 	default:
-		return nil, fmt.Errorf("invalid spec %s, expected %+v", config.Spec, []schema.SchemaSpecType{schema.OpenAPIv3Spec, schema.OpenAPIv2Spec})
+		return nil, nil, fmt.Errorf("invalid spec %s, expected %+v", spec, []schema.SchemaSpecType{schema.OpenAPIv3Spec, schema.OpenAPIv2Spec, schema.NDCSpec})
 	}
 
 	if result == nil {
-		return nil, errors.Join(errs...)
+		return nil, nil, errors.Join(errs...)
 	} else if len(errs) > 0 {
 		logger.Error(errors.Join(errs...).Error())
 	}
 
-	return utils.ApplyPatchToRestSchema(result, config.PatchAfter)
+	result, err = utils.ApplyPatchToRestSchema(result, config.PatchAfter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, report, nil
 }
 
 // ResolveConvertConfigArguments resolves convert config arguments
@@ -223,14 +330,34 @@ func ResolveConvertConfigArguments(config *ConvertConfig, configDir string, args
 		if args.Strict {
 			config.Strict = args.Strict
 		}
+		if args.Tolerant {
+			config.Tolerant = args.Tolerant
+		}
+		if args.DetectCollections {
+			config.DetectCollections = args.DetectCollections
+		}
+		if args.ResponseHeaders {
+			config.ResponseHeaders = args.ResponseHeaders
+		}
+		if args.StatusCodeResponses {
+			config.StatusCodeResponses = args.StatusCodeResponses
+		}
+		if args.TypedMaps {
+			config.TypedMaps = args.TypedMaps
+		}
+		if args.Int64Representation != "" {
+			config.Int64Representation = schema.NumberRepresentation(args.Int64Representation)
+		}
+		if args.DecimalRepresentation != "" {
+			config.DecimalRepresentation = schema.NumberRepresentation(args.DecimalRepresentation)
+		}
 		if len(args.AllowedContentTypes) > 0 {
 			config.AllowedContentTypes = args.AllowedContentTypes
 		}
+		if len(args.ExtraMethods) > 0 {
+			config.ExtraMethods = args.ExtraMethods
+		}
 	}
-	if config.Spec == "" {
-		config.Spec = schema.OAS3Spec
-	}
-
 	if args != nil && args.File != "" {
 		config.File = args.File
 	} else if config.File != "" {
@@ -243,6 +370,18 @@ func ResolveConvertConfigArguments(config *ConvertConfig, configDir string, args
 		config.Output = utils.ResolveFilePath(configDir, config.Output)
 	}
 
+	if args != nil && args.Report != "" {
+		config.Report = args.Report
+	} else if config.Report != "" {
+		config.Report = utils.ResolveFilePath(configDir, config.Report)
+	}
+
+	if args != nil && args.SourceMap != "" {
+		config.SourceMap = args.SourceMap
+	} else if config.SourceMap != "" {
+		config.SourceMap = utils.ResolveFilePath(configDir, config.SourceMap)
+	}
+
 	if args != nil && len(args.PatchBefore) > 0 {
 		config.PatchBefore = make([]utils.PatchConfig, len(args.PatchBefore))
 		for i, p := range args.PatchBefore {
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hasura/ndc-rest-schema/openapi"
 	"github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
 )
 
 var nopLogger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
@@ -55,6 +57,21 @@ func TestConvertToNDCSchema(t *testing.T) {
 			noOutput: true,
 			format:   schema.SchemaFileYAML,
 		},
+		{
+			name:     "openapi2_auto_detect",
+			filePath: "../openapi/testdata/petstore2/swagger.json",
+			expected: "../openapi/testdata/petstore2/expected.json",
+		},
+		{
+			name:     "ndc_auto_detect",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+			expected: "../openapi/testdata/petstore3/expected.json",
+		},
+		{
+			name:     "asyncapi_unsupported",
+			filePath: "../utils/testdata/spec/asyncapi.yaml",
+			errorMsg: "AsyncAPI 2.6.0 documents are not supported",
+		},
 		{
 			name:     "invalid_output_format",
 			filePath: "../openapi/testdata/petstore2/swagger.json",
@@ -193,3 +210,62 @@ func assertDeepEqual(t *testing.T, expected any, reality any, msgs ...string) {
 		t.FailNow()
 	}
 }
+
+func TestConvertNDCSchemaYAML(t *testing.T) {
+	jsonFilePath := "../openapi/testdata/onesignal/expected.json"
+	patchAfter := []utils.PatchConfig{{Path: "../openapi/testdata/onesignal/patch-after.json"}}
+	jsonSchema, err := utils.ReadSchemaFile(jsonFilePath)
+	assertNoError(t, err)
+
+	tempDir := t.TempDir()
+	yamlFilePath := fmt.Sprintf("%s/schema.yaml", tempDir)
+	assertNoError(t, utils.WriteSchemaFile(yamlFilePath, jsonSchema))
+
+	convert := func(filePath string) *schema.NDCRestSchema {
+		output, err := ConvertToNDCSchema(&ConvertConfig{
+			File:       filePath,
+			Spec:       schema.NDCSpec,
+			PatchAfter: patchAfter,
+		}, nopLogger)
+		assertNoError(t, err)
+		return output
+	}
+
+	expected := convert(jsonFilePath)
+	output := convert(yamlFilePath)
+	if len(output.Procedures) == 0 || len(output.Functions) != 0 {
+		t.Errorf("expected the patch to be applied to the YAML schema, got %d functions and %d procedures", len(output.Functions), len(output.Procedures))
+		t.FailNow()
+	}
+	assertDeepEqual(t, expected.Settings, output.Settings)
+	assertDeepEqual(t, expected.Procedures, output.Procedures)
+	assertDeepEqual(t, expected.ScalarTypes, output.ScalarTypes)
+}
+
+func TestConvertReport(t *testing.T) {
+	tempDir := t.TempDir()
+	reportPath := fmt.Sprintf("%s/report.json", tempDir)
+	assertNoError(t, CommandConvertToNDCSchema(&ConvertCommandArguments{
+		File:   "../openapi/testdata/petstore2/swagger.json",
+		Spec:   string(schema.OAS2Spec),
+		Output: fmt.Sprintf("%s/schema.json", tempDir),
+		Report: reportPath,
+	}, nopLogger))
+
+	rawReport, err := os.ReadFile(reportPath)
+	assertNoError(t, err)
+	var report openapi.ConversionReport
+	assertNoError(t, json.Unmarshal(rawReport, &report))
+	if len(report.SkippedOperations) == 0 || len(report.JSONFallbacks) == 0 {
+		t.Errorf("expected skipped operations and JSON fallbacks in the report, got: %s", string(rawReport))
+	}
+
+	_, ndcReport, err := ConvertToNDCSchemaWithReport(&ConvertConfig{
+		File: "../openapi/testdata/petstore2/expected.json",
+		Spec: schema.NDCSpec,
+	}, nopLogger)
+	assertNoError(t, err)
+	if ndcReport != nil {
+		t.Errorf("expected no report for the NDC spec, got: %+v", ndcReport)
+	}
+}
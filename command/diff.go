@@ -0,0 +1,78 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+)
+
+var errBreakingChanges = errors.New("found breaking changes")
+
+// DiffCommandArguments represent available command arguments for the diff command
+type DiffCommandArguments struct {
+	Source string `help:"Path of the original NDC REST schema file." arg:""`
+	Target string `help:"Path of the new NDC REST schema file." arg:""`
+	Format string `help:"The output format, is one of text, json, yaml." enum:"text,json,yaml" default:"text"`
+	Output string `help:"The location where the diff result will be written. Print to stdout if not set" short:"o"`
+}
+
+// DiffSchemas compares two NDC REST schema files and reports breaking and non-breaking changes.
+// Returns an error if there are breaking changes
+func DiffSchemas(args *DiffCommandArguments, logger *slog.Logger) error {
+	logger.Debug(
+		"comparing NDC REST schemas",
+		slog.String("source", args.Source),
+		slog.String("target", args.Target),
+		slog.String("format", args.Format),
+		slog.String("output", args.Output),
+	)
+
+	source, err := utils.ReadSchemaFile(args.Source)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+	target, err := utils.ReadSchemaFile(args.Target)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	result := schema.DiffNDCRestSchema(source, target)
+
+	var resultBytes []byte
+	switch args.Format {
+	case "", "text":
+		resultBytes = []byte(result.String())
+	default:
+		format, err := schema.ParseSchemaFileFormat(args.Format)
+		if err != nil {
+			logger.Error("failed to parse format", slog.Any("error", err))
+			return err
+		}
+		resultBytes, err = utils.MarshalSchema(result, format)
+		if err != nil {
+			logger.Error("failed to encode the diff result", slog.Any("error", err))
+			return err
+		}
+	}
+
+	if args.Output != "" {
+		if err := os.WriteFile(args.Output, resultBytes, 0664); err != nil {
+			logger.Error(err.Error())
+			return err
+		}
+	} else {
+		fmt.Print(string(resultBytes))
+	}
+
+	if result.HasBreakingChanges() {
+		logger.Error(errBreakingChanges.Error(), slog.Int("count", len(result.Breaking)))
+		return errBreakingChanges
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	testCases := []struct {
+		name             string
+		source           string
+		target           string
+		format           string
+		expectedBreaking int
+		errorMsg         string
+	}{
+		{
+			name:     "file_not_found",
+			source:   "foo.json",
+			target:   "../openapi/testdata/onesignal/expected.json",
+			errorMsg: "failed to read content from foo.json: open foo.json: no such file or directory",
+		},
+		{
+			name:   "no_changes",
+			source: "../openapi/testdata/onesignal/expected.json",
+			target: "../openapi/testdata/onesignal/expected.json",
+			format: "json",
+		},
+		{
+			name:             "breaking_changes",
+			source:           "../openapi/testdata/onesignal/expected.json",
+			target:           "../openapi/testdata/onesignal/expected-patch.json",
+			format:           "json",
+			expectedBreaking: 16,
+			errorMsg:         "found breaking changes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputFilePath := fmt.Sprintf("%s/output.json", t.TempDir())
+			err := DiffSchemas(&DiffCommandArguments{
+				Source: tc.source,
+				Target: tc.target,
+				Format: tc.format,
+				Output: outputFilePath,
+			}, nopLogger)
+
+			if tc.errorMsg != "" {
+				assertError(t, err, tc.errorMsg)
+			} else {
+				assertNoError(t, err)
+			}
+			if tc.format == "" {
+				return
+			}
+
+			outputBytes, err := os.ReadFile(outputFilePath)
+			assertNoError(t, err)
+			var output schema.SchemaDiff
+			assertNoError(t, json.Unmarshal(outputBytes, &output))
+			assertDeepEqual(t, tc.expectedBreaking, len(output.Breaking))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hasura/ndc-rest-schema/openapi"
+	"github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+)
+
+// ExportCommandArguments represent available sub-commands for the export command
+type ExportCommandArguments struct {
+	OpenAPI ExportOpenAPICommandArguments `cmd:"" name:"openapi" help:"Export the NDC REST schema to an OpenAPI 3.0 document. For example:\n ndc-rest-schema export openapi -f schema.json -o openapi.yaml"`
+}
+
+// ExportOpenAPICommandArguments represent available command arguments for the export openapi command
+type ExportOpenAPICommandArguments struct {
+	File   string `help:"Path of the NDC REST schema file to be exported." short:"f" required:""`
+	Output string `help:"The location where the OpenAPI document will be generated. Print to stdout if not set" short:"o"`
+	Format string `help:"The output format, is one of json, yaml. If not set, detect the format from the output file extension, or use json"`
+	Title  string `help:"The title of the API document"`
+}
+
+// ExportOpenAPI exports the NDC REST schema file to an OpenAPI 3.0 document
+func ExportOpenAPI(args *ExportOpenAPICommandArguments, logger *slog.Logger) error {
+	logger.Debug(
+		"exporting the NDC REST schema to OpenAPI",
+		slog.String("file", args.File),
+		slog.String("output", args.Output),
+		slog.String("format", args.Format),
+		slog.String("title", args.Title),
+	)
+
+	restSchema, err := utils.ReadSchemaFile(args.File)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	document, err := openapi.NDCSchemaToOpenAPIv3(restSchema, openapi.ExportOptions{
+		Title:  args.Title,
+		Logger: logger,
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	// an explicit format takes precedence over the extension of the output file
+	format := schema.SchemaFileJSON
+	if args.Format != "" {
+		format, err = schema.ParseSchemaFileFormat(args.Format)
+	} else if args.Output != "" {
+		format, err = schema.ParseSchemaFileFormat(strings.TrimLeft(filepath.Ext(args.Output), "."))
+	}
+	if err != nil {
+		logger.Error("failed to parse format", slog.Any("error", err))
+		return err
+	}
+
+	var resultBytes []byte
+	switch format {
+	case schema.SchemaFileYAML:
+		resultBytes, err = document.Render()
+	default:
+		resultBytes, err = document.RenderJSON("  ")
+	}
+	if err != nil {
+		logger.Error("failed to encode the OpenAPI document", slog.Any("error", err))
+		return err
+	}
+
+	if args.Output != "" {
+		if err := os.WriteFile(args.Output, resultBytes, 0664); err != nil {
+			logger.Error("failed to write the OpenAPI document", slog.String("error", err.Error()))
+			return err
+		}
+		logger.Info("exported successfully")
+		return nil
+	}
+
+	fmt.Print(string(resultBytes))
+	return nil
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExportOpenAPI(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filePath string
+		output   string
+		format   string
+		title    string
+		expected string
+		errorMsg string
+	}{
+		{
+			name:     "file_not_found",
+			filePath: "foo.json",
+			errorMsg: "failed to read content from foo.json: open foo.json: no such file or directory",
+		},
+		{
+			name:     "json",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+			output:   "openapi.json",
+			title:    "Petstore",
+			expected: `"title": "Petstore"`,
+		},
+		{
+			name:     "yaml",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+			output:   "openapi.yaml",
+			expected: "openapi: 3.0.3",
+		},
+		{
+			name:     "explicit_format",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+			output:   "openapi.txt",
+			format:   "yaml",
+			expected: "openapi: 3.0.3",
+		},
+		{
+			name:     "format_overrides_extension",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+			output:   "openapi.yaml",
+			format:   "json",
+			expected: `"openapi": "3.0.3"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var outputFilePath string
+			if tc.output != "" {
+				outputFilePath = fmt.Sprintf("%s/%s", t.TempDir(), tc.output)
+			}
+			err := ExportOpenAPI(&ExportOpenAPICommandArguments{
+				File:   tc.filePath,
+				Output: outputFilePath,
+				Format: tc.format,
+				Title:  tc.title,
+			}, nopLogger)
+
+			if tc.errorMsg != "" {
+				assertError(t, err, tc.errorMsg)
+				return
+			}
+			assertNoError(t, err)
+
+			outputBytes, err := os.ReadFile(outputFilePath)
+			assertNoError(t, err)
+			if !strings.Contains(string(outputBytes), tc.expected) {
+				t.Errorf("expected the output to contain %s", tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+)
+
+// MergeCommandArguments represent available command arguments for the merge command
+type MergeCommandArguments struct {
+	Files    []string `help:"Paths of NDC REST schema files to be merged. The source name that is used as the prefix can be set before the path, e.g. petstore=petstore.json. The file name is used by default" short:"f" required:""`
+	Output   string   `help:"The location where the merged schema file will be generated. Print to stdout if not set" short:"o"`
+	Format   string   `help:"The output format, is one of json, yaml. If the output is set, automatically detect the format in the output file extension" default:"json"`
+	Conflict string   `help:"The policy to resolve name collisions, is one of error, prefix, keep-first" enum:"error,prefix,keep-first" default:"error"`
+}
+
+// MergeSchemas combines many NDC REST schema files into one
+func MergeSchemas(args *MergeCommandArguments, logger *slog.Logger) error {
+	logger.Debug(
+		"merging NDC REST schemas",
+		slog.Any("files", args.Files),
+		slog.String("output", args.Output),
+		slog.String("format", args.Format),
+		slog.String("conflict", args.Conflict),
+	)
+
+	sources := make([]schema.NDCRestSchemaSource, len(args.Files))
+	for i, file := range args.Files {
+		name, filePath := parseMergeSourceFile(file)
+		sm, err := utils.ReadSchemaFile(filePath)
+		if err != nil {
+			logger.Error(err.Error())
+			return err
+		}
+		sources[i] = schema.NDCRestSchemaSource{
+			Name:   name,
+			Schema: sm,
+		}
+	}
+
+	result, err := schema.MergeNDCRestSchemas(sources, schema.MergeConflictPolicy(args.Conflict))
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	if args.Output != "" {
+		if err := utils.WriteSchemaFile(args.Output, result); err != nil {
+			logger.Error("failed to write schema file", slog.String("error", err.Error()))
+			return err
+		}
+		logger.Info("merged successfully")
+		return nil
+	}
+
+	format := schema.SchemaFileJSON
+	if args.Format != "" {
+		format, err = schema.ParseSchemaFileFormat(args.Format)
+		if err != nil {
+			logger.Error("failed to parse format", slog.Any("error", err))
+			return err
+		}
+	}
+
+	resultBytes, err := utils.MarshalSchema(result, format)
+	if err != nil {
+		logger.Error("failed to encode schema", slog.Any("error", err))
+		return err
+	}
+
+	fmt.Print(string(resultBytes))
+	return nil
+}
+
+// parse the source name and file path from the name=path format.
+// The name is generated from the file name if not set
+func parseMergeSourceFile(input string) (string, string) {
+	name, filePath, found := strings.Cut(input, "=")
+	if found && name != "" && !strings.Contains(name, "/") {
+		return name, filePath
+	}
+	fileName := filepath.Base(input)
+	return utils.ToCamelCase(strings.TrimSuffix(fileName, filepath.Ext(fileName))), input
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hasura/ndc-rest-schema/utils"
+)
+
+func TestMergeSchemas(t *testing.T) {
+	testCases := []struct {
+		name               string
+		files              []string
+		conflict           string
+		expectedFunctions  int
+		expectedProcedures int
+		errorMsg           string
+	}{
+		{
+			name:     "file_not_found",
+			files:    []string{"foo.json"},
+			errorMsg: "failed to read content from foo.json: open foo.json: no such file or directory",
+		},
+		{
+			name:     "conflict_error",
+			files:    []string{"../openapi/testdata/petstore3/expected.json", "../openapi/testdata/petstore2/expected.json"},
+			conflict: "error",
+			errorMsg: "expected: function getInventory conflicts with a previous schema",
+		},
+		{
+			name:               "conflict_prefix",
+			files:              []string{"../openapi/testdata/petstore3/expected.json", "store2=../openapi/testdata/petstore2/expected.json"},
+			conflict:           "prefix",
+			expectedFunctions:  17,
+			expectedProcedures: 26,
+		},
+		{
+			name:               "conflict_keep_first",
+			files:              []string{"../openapi/testdata/petstore3/expected.json", "../openapi/testdata/petstore2/expected.json"},
+			conflict:           "keep-first",
+			expectedFunctions:  9,
+			expectedProcedures: 17,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputFilePath := fmt.Sprintf("%s/output.json", t.TempDir())
+			err := MergeSchemas(&MergeCommandArguments{
+				Files:    tc.files,
+				Output:   outputFilePath,
+				Conflict: tc.conflict,
+			}, nopLogger)
+
+			if tc.errorMsg != "" {
+				assertError(t, err, tc.errorMsg)
+				return
+			}
+			assertNoError(t, err)
+
+			output, err := utils.ReadSchemaFile(outputFilePath)
+			assertNoError(t, err)
+			assertNoError(t, output.Validate())
+			assertDeepEqual(t, tc.expectedFunctions, len(output.Functions))
+			assertDeepEqual(t, tc.expectedProcedures, len(output.Procedures))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package command
+
+import (
+	"log/slog"
+
+	"github.com/hasura/ndc-rest-schema/utils"
+)
+
+// ValidateCommandArguments represent available command arguments for the validate command
+type ValidateCommandArguments struct {
+	File string `help:"Path of the NDC REST schema file to be validated." short:"f" required:""`
+}
+
+// ValidateSchema loads the NDC REST schema file and validates its content
+func ValidateSchema(args *ValidateCommandArguments, logger *slog.Logger) error {
+	logger.Debug("validating the NDC REST schema", slog.String("file", args.File))
+
+	restSchema, err := utils.ReadSchemaFile(args.File)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	if err := restSchema.Validate(); err != nil {
+		logger.Error("the schema is invalid", slog.String("file", args.File))
+		logger.Error(err.Error())
+		return err
+	}
+
+	logger.Info("the schema is valid", slog.String("file", args.File))
+	return nil
+}
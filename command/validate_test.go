@@ -0,0 +1,42 @@
+package command
+
+import (
+	"testing"
+)
+
+func TestValidateSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filePath string
+		errorMsg string
+	}{
+		{
+			name:     "file_not_found",
+			filePath: "foo.json",
+			errorMsg: "failed to read content from foo.json: open foo.json: no such file or directory",
+		},
+		{
+			name:     "success",
+			filePath: "../openapi/testdata/petstore3/expected.json",
+		},
+		{
+			name:     "type_not_found",
+			filePath: "../openapi/testdata/onesignal/expected-patch.json",
+			errorMsg: "procedure create_notification: result type: type CreateNotificationSuccessResponse does not exist",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSchema(&ValidateCommandArguments{
+				File: tc.filePath,
+			}, nopLogger)
+
+			if tc.errorMsg != "" {
+				assertError(t, err, tc.errorMsg)
+				return
+			}
+			assertNoError(t, err)
+		})
+	}
+}
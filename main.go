@@ -16,6 +16,10 @@ var cli struct {
 	LogLevel  string                            `help:"Log level." enum:"debug,info,warn,error" default:"info"`
 	Convert   command.ConvertCommandArguments   `cmd:"" help:"Convert API spec to NDC schema. For example:\n ndc-rest-schema convert -f petstore.yaml -o petstore.json"`
 	Json2Yaml command.Json2YamlCommandArguments `cmd:"" name:"json2yaml" help:"Convert JSON file to YAML. For example:\n ndc-rest-schema json2yaml -f petstore.json -o petstore.yaml"`
+	Validate  command.ValidateCommandArguments  `cmd:"" help:"Validate the NDC REST schema file. For example:\n ndc-rest-schema validate -f petstore.json"`
+	Diff      command.DiffCommandArguments      `cmd:"" help:"Compare two NDC REST schema files and report breaking changes. For example:\n ndc-rest-schema diff old.json new.json"`
+	Merge     command.MergeCommandArguments     `cmd:"" help:"Merge many NDC REST schema files into one. For example:\n ndc-rest-schema merge -f petstore.json -f github.json -o schema.json --conflict prefix"`
+	Export    command.ExportCommandArguments    `cmd:"" help:"Export the NDC REST schema to other API specifications."`
 	Version   struct{}                          `cmd:"" help:"Print the CLI version."`
 }
 
@@ -33,6 +37,14 @@ func main() {
 		err = command.CommandConvertToNDCSchema(&cli.Convert, logger)
 	case "json2yaml":
 		err = command.Json2Yaml(&cli.Json2Yaml, logger)
+	case "validate":
+		err = command.ValidateSchema(&cli.Validate, logger)
+	case "diff <source> <target>":
+		err = command.DiffSchemas(&cli.Diff, logger)
+	case "merge":
+		err = command.MergeSchemas(&cli.Merge, logger)
+	case "export openapi":
+		err = command.ExportOpenAPI(&cli.Export.OpenAPI, logger)
 	case "version":
 		_, _ = fmt.Print(version.BuildVersion)
 	default:
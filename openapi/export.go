@@ -0,0 +1,15 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-rest-schema/openapi/internal"
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+type ExportOptions internal.ExportOptions
+
+// NDCSchemaToOpenAPIv3 exports the NDC REST schema to an OpenAPI 3.0 document model.
+// Use the Render or RenderJSON method of the document to encode the output
+func NDCSchemaToOpenAPIv3(ndcSchema *rest.NDCRestSchema, options ExportOptions) (*v3.Document, error) {
+	return internal.NewOAS3Exporter(ndcSchema, internal.ExportOptions(options)).Build()
+}
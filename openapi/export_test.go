@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+	"github.com/pb33f/libopenapi"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+func TestNDCSchemaToOpenAPIv3(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Source  string
+		Options ConvertOptions
+		Assert  func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema)
+	}{
+		{
+			Name:   "petstore3",
+			Source: "testdata/petstore3/expected.json",
+		},
+		{
+			Name:   "petstore2",
+			Source: "testdata/petstore2/expected.json",
+		},
+		{
+			Name:   "onesignal",
+			Source: "testdata/onesignal/expected.json",
+		},
+		// operation kinds are kept in the x-ndc-operation extension
+		{
+			Name:   "operation_kinds",
+			Source: "testdata/operation_kinds/expected-openapi3.json",
+		},
+		{
+			Name:   "extra_methods",
+			Source: "testdata/extra_methods/expected-openapi3.json",
+			Options: ConvertOptions{
+				ExtraMethods: []string{"head", "options", "trace"},
+			},
+		},
+		// envelopes of response headers are kept
+		{
+			Name:   "response_headers",
+			Source: "testdata/response_headers/expected-openapi3.json",
+			Options: ConvertOptions{
+				ResponseHeaders: true,
+			},
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				assertDeepEqual(t, source.Functions[0].Request.Response, output.Functions[0].Request.Response)
+			},
+		},
+		// status code responses are kept
+		{
+			Name:   "status_codes",
+			Source: "testdata/status_codes/expected-openapi3.json",
+			Options: ConvertOptions{
+				StatusCodeResponses: true,
+			},
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				statusCodes := source.Procedures[0].Request.Response.StatusCodes
+				for _, proc := range output.Procedures {
+					if proc.Name == source.Procedures[0].Name {
+						assertDeepEqual(t, getSortedMapKeys(statusCodes), getSortedMapKeys(proc.Request.Response.StatusCodes))
+						assertDeepEqual(t, statusCodes["202"], proc.Request.Response.StatusCodes["202"])
+					}
+				}
+			},
+		},
+		// formats of scalar types are kept
+		{
+			Name:   "formats",
+			Source: "testdata/formats/expected-openapi3.json",
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				assertDeepEqual(t, source.ObjectTypes["Formats"], output.ObjectTypes["Formats"])
+				for _, key := range getSortedMapKeys(source.ScalarTypes) {
+					assertDeepEqual(t, source.ScalarTypes[key], output.ScalarTypes[key], key)
+				}
+			},
+		},
+		// numeric enum scalars keep the type
+		{
+			Name:   "numeric_enums",
+			Source: "testdata/numeric_enums/expected-openapi3.json",
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				for name, expected := range map[string]string{"TaskPriority": "integer", "TasksRatio": "number", "TaskStatus": "integer"} {
+					component := document.Components.Schemas.GetOrZero(name)
+					if component == nil {
+						t.Fatalf("expected the component schema %s", name)
+					}
+					assertDeepEqual(t, []string{expected}, component.Schema().Type, name)
+				}
+				for i, param := range source.Functions[0].Request.Parameters {
+					assertDeepEqual(t, param.Schema.Enum, output.Functions[0].Request.Parameters[i].Schema.Enum, param.Name)
+				}
+				assertDeepEqual(t, source.ObjectTypes["Task"], output.ObjectTypes["Task"])
+			},
+		},
+		// exclusive bounds are exported in the OpenAPI 3.0 boolean form
+		{
+			Name:   "keywords",
+			Source: "testdata/keywords/expected-openapi3.json",
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				assertDeepEqual(t, source.Procedures[0].Request.Parameters[0], output.Procedures[0].Request.Parameters[0])
+			},
+		},
+		// typed maps are exported as additionalProperties instead of arrays of entries
+		{
+			Name:   "typed_maps",
+			Source: "testdata/typed_maps/expected-openapi3.json",
+			Options: ConvertOptions{
+				TypedMaps: true,
+			},
+			Assert: func(t *testing.T, source *schema.NDCRestSchema, document *v3.Document, output *schema.NDCRestSchema) {
+				if _, ok := document.Components.Schemas.Get("PricesEntry"); ok {
+					t.Error("expected no component schema of map entries")
+				}
+				product := document.Components.Schemas.GetOrZero("Product").Schema()
+				for _, key := range []string{"names", "prices", "tags"} {
+					prop := product.Properties.GetOrZero(key).Schema()
+					assertDeepEqual(t, []string{"object"}, prop.Type, key)
+					if prop.AdditionalProperties == nil || prop.AdditionalProperties.A == nil {
+						t.Fatalf("%s: expected additionalProperties, got: %+v", key, prop)
+					}
+				}
+				assertDeepEqual(t, "#/components/schemas/Price", product.Properties.GetOrZero("prices").Schema().AdditionalProperties.A.GetReference())
+				bodySchema := document.Paths.PathItems.GetOrZero("/products/{id}").Put.RequestBody.Content.GetOrZero("application/json").Schema.Schema()
+				assertDeepEqual(t, []string{"object"}, bodySchema.Type)
+
+				assertDeepEqual(t, true, output.Procedures[0].Request.RequestBody.Schema.MapEntries)
+				assertDeepEqual(t, source.ObjectTypes["Product"].Fields["names"], output.ObjectTypes["Product"].Fields["names"])
+				assertDeepEqual(t, source.ObjectTypes["PricesEntry"].Fields["value"], output.ObjectTypes["ProductPricesEntry"].Fields["value"])
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assertNoError(t, err)
+			var source schema.NDCRestSchema
+			assertNoError(t, json.Unmarshal(sourceBytes, &source))
+
+			document, err := NDCSchemaToOpenAPIv3(&source, ExportOptions{})
+			assertNoError(t, err)
+			outputBytes, err := document.RenderJSON("  ")
+			assertNoError(t, err)
+
+			outputDocument, err := libopenapi.NewDocument(outputBytes)
+			assertNoError(t, err)
+			if _, errs := outputDocument.BuildV3Model(); len(errs) > 0 {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			// the exported document can be converted back with the same operations
+			output, errs := OpenAPIv3ToNDCSchema(outputBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+			assertDeepEqual(t, getExportedOperations(&source), getExportedOperations(output))
+			if tc.Assert != nil {
+				tc.Assert(t, &source, document, output)
+			}
+		})
+	}
+
+	t.Run("conflict", func(t *testing.T) {
+		ndcSchema := schema.NewNDCRestSchema()
+		for _, name := range []string{"getPets", "listPets"} {
+			fn := &schema.RESTFunctionInfo{
+				Request: &schema.Request{URL: "/pets", Method: "get"},
+			}
+			fn.Name = name
+			ndcSchema.Functions = append(ndcSchema.Functions, fn)
+		}
+		_, err := NDCSchemaToOpenAPIv3(ndcSchema, ExportOptions{})
+		if err == nil || !strings.Contains(err.Error(), "function listPets: get /pets conflicts with operation getPets") {
+			t.Fatalf("expected conflict error, got: %v", err)
+		}
+	})
+}
+
+func getExportedOperations(ndcSchema *schema.NDCRestSchema) []string {
+	var results []string
+	getDescription := func(description *string) string {
+		if description == nil {
+			return ""
+		}
+		return *description
+	}
+	for _, fn := range ndcSchema.Functions {
+		results = append(results, strings.Join([]string{"function", fn.Name, fn.Request.Method, fn.Request.URL, getDescription(fn.Description)}, " "))
+	}
+	for _, proc := range ndcSchema.Procedures {
+		results = append(results, strings.Join([]string{"procedure", proc.Name, proc.Request.Method, proc.Request.URL, getDescription(proc.Description)}, " "))
+	}
+	slices.Sort(results)
+	return results
+}
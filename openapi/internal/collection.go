@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const collectionExtension = "x-ndc-collection"
+
+// well-known query parameter names of pagination and sorting, in lowercase without separators
+var (
+	limitParameterNames          = []string{"limit", "pagesize", "perpage", "maxresults", "size", "top"}
+	offsetParameterNames         = []string{"offset", "skip", "start", "startindex"}
+	pageParameterNames           = []string{"page", "pagenumber", "pageno", "pagenum", "pageindex"}
+	cursorParameterNames         = []string{"cursor", "after", "startingafter", "pagetoken", "nextpagetoken", "nexttoken", "continuationtoken", "marker", "pagecursor"}
+	orderByParameterNames        = []string{"sort", "sortby", "orderby", "sortfield"}
+	orderDirectionParameterNames = []string{"direction", "sortorder", "sortdirection", "sortdir", "order"}
+	itemsFieldNames              = []string{"data", "items", "results", "records", "entries", "values", "list", "nodes"}
+	nextCursorFieldNames         = []string{"nextcursor", "nextpagetoken", "nexttoken", "next", "endcursor", "cursor"}
+	paginationFieldNames         = []string{"meta", "pagination", "pageinfo", "paging", "metadata"}
+)
+
+// collectionSettings represents the resolved collection settings of a GET operation
+type collectionSettings struct {
+	// The operation is declared as a collection by the x-ndc-collection extension
+	Explicit bool
+	// The operation is matched by the detection mode and must be paginated
+	Detected bool
+	// Pagination settings that override detected parameters
+	Pagination *rest.PaginationInfo
+}
+
+// collectionExtensionValue represents the object value of the x-ndc-collection extension
+type collectionExtensionValue struct {
+	Pagination *rest.PaginationInfo `yaml:"pagination,omitempty"`
+}
+
+// resolve collection settings of the GET operation from the x-ndc-collection extension, config rules and the detection mode in order.
+// Returns nil if the operation shouldn't be converted to a collection
+func getCollectionSettings(options *ConvertOptions, extensionNode *yaml.Node, input utils.OperationFilterInput) (*collectionSettings, error) {
+	if extensionNode != nil {
+		switch extensionNode.Kind {
+		case yaml.ScalarNode:
+			var enabled bool
+			if err := extensionNode.Decode(&enabled); err != nil {
+				return nil, fmt.Errorf("%s: expected a boolean or an object, got %s", collectionExtension, extensionNode.Value)
+			}
+			if !enabled {
+				return nil, nil
+			}
+			return &collectionSettings{Explicit: true}, nil
+		case yaml.MappingNode:
+			var value collectionExtensionValue
+			if err := extensionNode.Decode(&value); err != nil {
+				return nil, fmt.Errorf("%s: %s", collectionExtension, err)
+			}
+			if value.Pagination != nil && value.Pagination.Style != "" && !value.Pagination.Style.IsValid() {
+				return nil, fmt.Errorf("%s: invalid pagination style %s", collectionExtension, value.Pagination.Style)
+			}
+			return &collectionSettings{Explicit: true, Pagination: value.Pagination}, nil
+		default:
+			return nil, fmt.Errorf("%s: expected a boolean or an object", collectionExtension)
+		}
+	}
+
+	if rule := utils.FindCollectionRule(options.Collections, input); rule != nil {
+		return &collectionSettings{Pagination: rule.Pagination}, nil
+	}
+	if options.DetectCollections {
+		return &collectionSettings{Detected: true}, nil
+	}
+	return nil, nil
+}
+
+// collectionCandidate represents the function of a GET operation that may be converted to a collection
+type collectionCandidate struct {
+	PathKey     string
+	OperationID string
+	Location    SourceLocation
+	Function    *rest.RESTFunctionInfo
+	Settings    *collectionSettings
+}
+
+// convert candidate functions to collections. It must be called after all object types are converted,
+// so the item type of the list can be resolved. Errors are handled by the callback
+func applyCollectionCandidates(sm *rest.NDCRestSchema, options *ConvertOptions, sourceMap *SourceMap, candidates []collectionCandidate, handleError func(candidate collectionCandidate, err error) error) error {
+	for _, candidate := range candidates {
+		collection, err := convertFunctionToCollection(sm, options, candidate.Function, candidate.Settings)
+		if err != nil {
+			if err := handleError(candidate, err); err != nil {
+				return err
+			}
+		} else if collection == nil {
+			continue
+		}
+
+		sm.Functions = slices.DeleteFunc(sm.Functions, func(fn *rest.RESTFunctionInfo) bool {
+			return fn == candidate.Function
+		})
+		if collection != nil {
+			sm.Collections = append(sm.Collections, collection)
+			sourceMap.setCollection(collection.Name, candidate.Location)
+		}
+	}
+	return nil
+}
+
+// convertFunctionToCollection converts the function of a list endpoint to a collection.
+// Returns nil if the function doesn't return a list of objects, or isn't paginated in the detection mode.
+// Operations that are declared by the x-ndc-collection extension return an error instead
+func convertFunctionToCollection(sm *rest.NDCRestSchema, options *ConvertOptions, fn *rest.RESTFunctionInfo, settings *collectionSettings) (*rest.RESTCollectionInfo, error) {
+	if settings == nil {
+		return nil, nil
+	}
+	collection, reason := buildCollectionFromFunction(sm, fn, settings)
+	if reason == "" {
+		return collection, nil
+	}
+	if settings.Explicit {
+		return nil, fmt.Errorf("%s: cannot convert function %s to a collection: %s", collectionExtension, fn.Name, reason)
+	}
+	options.Logger.Debug("keep the list endpoint as a function",
+		slog.String("name", fn.Name),
+		slog.String("reason", reason),
+	)
+	return nil, nil
+}
+
+// build the collection from the function. Returns the reason if the function can't be converted
+func buildCollectionFromFunction(sm *rest.NDCRestSchema, fn *rest.RESTFunctionInfo, settings *collectionSettings) (*rest.RESTCollectionInfo, string) {
+	if fn.Request == nil {
+		return nil, "the request is empty"
+	}
+
+	pagination := rest.PaginationInfo{}
+	if settings.Pagination != nil {
+		pagination = *settings.Pagination
+	}
+
+	typeName, itemsField, envelope, err := getCollectionItemType(sm, fn.ResultType, pagination.ItemsField)
+	if err != nil {
+		return nil, err.Error()
+	}
+	pagination.ItemsField = itemsField
+
+	detectPaginationParameters(&pagination, fn.Request.Parameters)
+	if pagination.Style == "" {
+		switch {
+		case pagination.OffsetParameter != "":
+			pagination.Style = rest.PaginationOffset
+		case pagination.PageParameter != "":
+			pagination.Style = rest.PaginationPage
+		case pagination.CursorParameter != "":
+			pagination.Style = rest.PaginationCursor
+		}
+	}
+	if pagination.Style == rest.PaginationCursor && pagination.NextCursorField == "" && envelope != nil {
+		pagination.NextCursorField = findNextCursorField(sm, *envelope, 0)
+	}
+
+	var paginationParams []string
+	if pagination.Style != "" {
+		if err := pagination.Validate(); err != nil {
+			return nil, err.Error()
+		}
+		paginationParams = pagination.ParameterNames()
+	} else if settings.Detected {
+		return nil, "no pagination parameter"
+	}
+
+	arguments := schema.CollectionInfoArguments{}
+	for key, arg := range fn.Arguments {
+		arguments[key] = arg
+	}
+	for _, param := range fn.Request.Parameters {
+		if param.In != rest.InQuery || param.Name == "" || !slices.Contains(paginationParams, param.Name) {
+			continue
+		}
+		argName := param.ArgumentName
+		if argName == "" {
+			argName = param.Name
+		}
+		delete(arguments, argName)
+	}
+
+	request := fn.Request.Clone()
+	if pagination.Style != "" {
+		request.Pagination = &pagination
+	}
+
+	return &rest.RESTCollectionInfo{
+		Request: request,
+		CollectionInfo: schema.CollectionInfo{
+			Name:                  fn.Name,
+			Description:           fn.Description,
+			Arguments:             arguments,
+			Type:                  typeName,
+			ForeignKeys:           schema.CollectionInfoForeignKeys{},
+			UniquenessConstraints: schema.CollectionInfoUniquenessConstraints{},
+		},
+	}, ""
+}
+
+// get the object type of collection items from the result type of the function.
+// The result type can be an array of objects, or an object that contains the array in one of its fields
+func getCollectionItemType(sm *rest.NDCRestSchema, resultType schema.Type, itemsField string) (string, string, *schema.ObjectType, error) {
+	if resultType == nil {
+		return "", "", nil, errors.New("the result type is empty")
+	}
+	ty := unwrapNullableType(resultType.Interface())
+	if arrayType, ok := ty.(*schema.ArrayType); ok {
+		if itemsField != "" {
+			return "", "", nil, fmt.Errorf("the result type is an array, the items field %s can't be used", itemsField)
+		}
+		name, err := getObjectTypeName(sm, arrayType.ElementType)
+		return name, "", nil, err
+	}
+
+	envelopeName := getNamedType(ty, false, "")
+	envelope, ok := sm.ObjectTypes[envelopeName]
+	if !ok {
+		return "", "", nil, errors.New("the result type isn't an array or object")
+	}
+	if itemsField != "" {
+		field, ok := envelope.Fields[itemsField]
+		if !ok {
+			return "", "", nil, fmt.Errorf("the items field %s doesn't exist in the object type %s", itemsField, envelopeName)
+		}
+		arrayType, ok := unwrapNullableType(field.Type.Interface()).(*schema.ArrayType)
+		if !ok {
+			return "", "", nil, fmt.Errorf("the items field %s isn't an array", itemsField)
+		}
+		name, err := getObjectTypeName(sm, arrayType.ElementType)
+		return name, itemsField, &envelope, err
+	}
+
+	var candidates []string
+	for _, key := range getSortedKeys(envelope.Fields) {
+		arrayType, ok := unwrapNullableType(envelope.Fields[key].Type.Interface()).(*schema.ArrayType)
+		if !ok {
+			continue
+		}
+		if _, err := getObjectTypeName(sm, arrayType.ElementType); err == nil {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) > 1 {
+		candidates = slices.DeleteFunc(candidates, func(key string) bool {
+			return !slices.Contains(itemsFieldNames, normalizeParameterName(key))
+		})
+	}
+	if len(candidates) != 1 {
+		return "", "", nil, fmt.Errorf("cannot find the array field of items in the object type %s", envelopeName)
+	}
+	arrayType := unwrapNullableType(envelope.Fields[candidates[0]].Type.Interface()).(*schema.ArrayType)
+	name, err := getObjectTypeName(sm, arrayType.ElementType)
+	return name, candidates[0], &envelope, err
+}
+
+func getObjectTypeName(sm *rest.NDCRestSchema, input schema.Type) (string, error) {
+	name := getNamedType(unwrapNullableType(input.Interface()), false, "")
+	if _, ok := sm.ObjectTypes[name]; !ok {
+		return "", errors.New("items of the list aren't objects")
+	}
+	return name, nil
+}
+
+// fill empty pagination parameters with well-known query parameters of the request
+func detectPaginationParameters(pagination *rest.PaginationInfo, params []rest.RequestParameter) {
+	detect := func(current *string, names []string) {
+		if *current != "" {
+			return
+		}
+		for _, param := range params {
+			if param.In == rest.InQuery && slices.Contains(names, normalizeParameterName(param.Name)) {
+				*current = param.Name
+				return
+			}
+		}
+	}
+
+	detect(&pagination.LimitParameter, limitParameterNames)
+	switch pagination.Style {
+	case rest.PaginationOffset:
+		detect(&pagination.OffsetParameter, offsetParameterNames)
+	case rest.PaginationPage:
+		detect(&pagination.PageParameter, pageParameterNames)
+	case rest.PaginationCursor:
+		detect(&pagination.CursorParameter, cursorParameterNames)
+	default:
+		detect(&pagination.OffsetParameter, offsetParameterNames)
+		detect(&pagination.PageParameter, pageParameterNames)
+		detect(&pagination.CursorParameter, cursorParameterNames)
+	}
+	detect(&pagination.OrderByParameter, orderByParameterNames)
+	if pagination.OrderByParameter != "" {
+		detect(&pagination.OrderDirectionParameter, orderDirectionParameterNames)
+	}
+}
+
+// find the field path of the next cursor in the response object, including nested pagination objects, e.g. meta.next_cursor
+func findNextCursorField(sm *rest.NDCRestSchema, object schema.ObjectType, depth int) string {
+	keys := getSortedKeys(object.Fields)
+	for _, key := range keys {
+		if slices.Contains(nextCursorFieldNames, normalizeParameterName(key)) {
+			if _, ok := sm.ScalarTypes[getNamedType(object.Fields[key].Type.Interface(), false, "")]; ok {
+				return key
+			}
+		}
+	}
+	if depth > 0 {
+		return ""
+	}
+	for _, key := range keys {
+		if !slices.Contains(paginationFieldNames, normalizeParameterName(key)) {
+			continue
+		}
+		nested, ok := sm.ObjectTypes[getNamedType(object.Fields[key].Type.Interface(), false, "")]
+		if !ok {
+			continue
+		}
+		if field := findNextCursorField(sm, nested, depth+1); field != "" {
+			return fmt.Sprintf("%s.%s", key, field)
+		}
+	}
+	return ""
+}
+
+// normalize the parameter name to lowercase without separators, e.g. page[size] -> pagesize, per_page -> perpage
+func normalizeParameterName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "", "[", "", "]", "").Replace(name))
+}
+
+func unwrapNullableType(input schema.TypeEncoder) schema.TypeEncoder {
+	if nullable, ok := input.(*schema.NullableType); ok {
+		return unwrapNullableType(nullable.UnderlyingType.Interface())
+	}
+	return input
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
 )
 
 // OAS2Builder the NDC schema builder from OpenAPI 2.0 specification
@@ -22,6 +23,11 @@ type OAS2Builder struct {
 
 	schema           *rest.NDCRestSchema
 	typeUsageCounter TypeUsageCounter
+	report           *ConversionReport
+	// errors of skipped operations in tolerant mode
+	operationErrors []error
+	// functions of GET operations that may be converted to collections
+	collectionCandidates []collectionCandidate
 }
 
 // NewOAS2Builder creates an OAS3Builder instance
@@ -29,9 +35,13 @@ func NewOAS2Builder(schema *rest.NDCRestSchema, options ConvertOptions) *OAS2Bui
 	builder := &OAS2Builder{
 		schema:           schema,
 		typeUsageCounter: TypeUsageCounter{},
+		report:           NewConversionReport(),
 		ConvertOptions:   applyConvertOptions(options),
 	}
 
+	if builder.ConvertOptions.SourceMap {
+		builder.report.SourceMap = NewSourceMap()
+	}
 	setDefaultSettings(builder.schema.Settings, builder.ConvertOptions)
 	return builder
 }
@@ -41,7 +51,35 @@ func (oc *OAS2Builder) Schema() *rest.NDCRestSchema {
 	return oc.schema
 }
 
+// Report returns the conversion report
+func (oc *OAS2Builder) Report() *ConversionReport {
+	return oc.report
+}
+
+// OperationErrors returns errors of operations that are skipped in tolerant mode
+func (oc *OAS2Builder) OperationErrors() []error {
+	return oc.operationErrors
+}
+
 func (oc *OAS2Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v2.Swagger]) error {
+	if err := utils.ValidateOperationFilters(oc.Include, oc.Exclude); err != nil {
+		return err
+	}
+	if err := utils.ValidateCollectionRules(oc.Collections); err != nil {
+		return err
+	}
+	if err := utils.ValidateOperationKindRules(oc.OperationKinds); err != nil {
+		return err
+	}
+	if err := utils.ValidateScalarMappings(oc.ScalarMappings); err != nil {
+		return err
+	}
+	if err := validateNumberRepresentations(oc.ConvertOptions); err != nil {
+		return err
+	}
+	if err := validateExtraMethods(oc.ExtraMethods); err != nil {
+		return err
+	}
 
 	if docModel.Model.Info != nil {
 		oc.schema.Settings.Version = docModel.Model.Info.Version
@@ -87,7 +125,11 @@ func (oc *OAS2Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v2.
 	}
 
 	oc.schema.Settings.Security = convertSecurities(docModel.Model.Security)
+	if err := oc.convertCollections(); err != nil {
+		return err
+	}
 	cleanUnusedSchemaTypes(oc.schema, &oc.typeUsageCounter)
+	oc.report.SourceMap.prune(oc.schema)
 
 	return nil
 }
@@ -166,53 +208,98 @@ func (oc *OAS2Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v2.
 	pathKey := pathItem.Key()
 	pathValue := pathItem.Value()
 
-	funcGet, err := newOAS2OperationBuilder(oc).BuildFunction(pathKey, pathValue.Get)
-	if err != nil {
-		return err
-	}
-	if funcGet != nil {
-		oc.schema.Functions = append(oc.schema.Functions, funcGet)
-	}
-
-	procPost, err := newOAS2OperationBuilder(oc).BuildProcedure(pathKey, "post", pathValue.Post)
-	if err != nil {
-		return err
-	}
-	if procPost != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPost)
-	}
-
-	procPut, err := newOAS2OperationBuilder(oc).BuildProcedure(pathKey, "put", pathValue.Put)
-	if err != nil {
-		return err
-	}
-	if procPut != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPut)
+	for _, item := range []struct {
+		method    string
+		operation *v2.Operation
+	}{
+		{"get", pathValue.Get},
+		{"post", pathValue.Post},
+		{"put", pathValue.Put},
+		{"patch", pathValue.Patch},
+		{"delete", pathValue.Delete},
+		{"head", pathValue.Head},
+		{"options", pathValue.Options},
+	} {
+		if !isMethodEnabled(oc.ConvertOptions, item.method) || item.operation == nil {
+			continue
+		}
+		filterInput := utils.OperationFilterInput{
+			Path:        pathKey,
+			Method:      item.method,
+			OperationID: item.operation.OperationId,
+			Tags:        item.operation.Tags,
+		}
+		if !isOperationIncluded(oc.ConvertOptions, oc.report, filterInput) {
+			continue
+		}
+		kind, err := getOperationKind(oc.ConvertOptions, getExtensionNode(item.operation.Extensions, operationKindExtension), filterInput)
+		if err == nil && kind == rest.OperationFunction {
+			var function *rest.RESTFunctionInfo
+			function, err = newOAS2OperationBuilder(oc).BuildFunction(pathKey, item.method, item.operation)
+			if err == nil && function != nil {
+				err = oc.addFunction(pathKey, item.method, item.operation, function, getOperationNodeV2(pathValue, item.method))
+			}
+		} else if err == nil {
+			var procedure *rest.RESTProcedureInfo
+			procedure, err = newOAS2OperationBuilder(oc).BuildProcedure(pathKey, item.method, item.operation)
+			if err == nil && procedure != nil {
+				oc.schema.Procedures = append(oc.schema.Procedures, procedure)
+				oc.report.SourceMap.setProcedure(procedure.Name, newSourceLocation(buildJSONPointer("", "paths", pathKey, item.method), getOperationNodeV2(pathValue, item.method)))
+			}
+		}
+		if err != nil {
+			if err := handleOperationError(oc.ConvertOptions, oc.report, &oc.operationErrors, pathKey, item.method, item.operation.OperationId, err); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	procPatch, err := newOAS2OperationBuilder(oc).BuildProcedure(pathKey, "patch", pathValue.Patch)
-	if err != nil {
-		return err
-	}
-	if procPatch != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPatch)
+// add the function to the schema. The function of a GET operation is also added to collection candidates
+// if the operation is declared or detected as a list endpoint
+func (oc *OAS2Builder) addFunction(pathKey string, method string, operation *v2.Operation, fn *rest.RESTFunctionInfo, keyNode *yaml.Node) error {
+	var settings *collectionSettings
+	if method == "get" {
+		var err error
+		settings, err = getCollectionSettings(oc.ConvertOptions, getExtensionNode(operation.Extensions, collectionExtension), utils.OperationFilterInput{
+			Path:        pathKey,
+			Method:      method,
+			OperationID: operation.OperationId,
+			Tags:        operation.Tags,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	procDelete, err := newOAS2OperationBuilder(oc).BuildProcedure(pathKey, "delete", pathValue.Delete)
-	if err != nil {
-		return err
-	}
-	if procDelete != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procDelete)
+	location := newSourceLocation(buildJSONPointer("", "paths", pathKey, method), keyNode)
+	oc.schema.Functions = append(oc.schema.Functions, fn)
+	oc.report.SourceMap.setFunction(fn.Name, location)
+	if settings != nil {
+		oc.collectionCandidates = append(oc.collectionCandidates, collectionCandidate{
+			PathKey:     pathKey,
+			OperationID: operation.OperationId,
+			Location:    location,
+			Function:    fn,
+			Settings:    settings,
+		})
 	}
 	return nil
 }
 
+// convert candidate functions of list endpoints to collections
+func (oc *OAS2Builder) convertCollections() error {
+	return applyCollectionCandidates(oc.schema, oc.ConvertOptions, oc.report.SourceMap, oc.collectionCandidates, func(candidate collectionCandidate, err error) error {
+		return handleOperationError(oc.ConvertOptions, oc.report, &oc.operationErrors, candidate.PathKey, "get", candidate.OperationID, err)
+	})
+}
+
 // get and convert an OpenAPI data type to a NDC type
-func (oc *OAS2Builder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nullable bool, apiPath string, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+func (oc *OAS2Builder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nullable bool, apiPath string, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
 
 	if schemaProxy == nil {
-		return nil, nil, errParameterSchemaEmpty(fieldPaths)
+		return nil, nil, errParameterSchemaEmpty(fieldPaths, pointer)
 	}
 
 	innerSchema := schemaProxy.Schema()
@@ -223,17 +310,23 @@ func (oc *OAS2Builder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nul
 	var typeSchema *rest.TypeSchema
 	var err error
 
+	if schemaProxy.IsReference() {
+		pointer = getRefJSONPointer(schemaProxy.GetReference())
+	}
 	refName := getSchemaRefTypeNameV2(schemaProxy.GetReference())
 	// return early object from ref
-	if refName != "" && len(innerSchema.Type) > 0 && innerSchema.Type[0] == "object" {
+	if refName != "" && len(innerSchema.Type) > 0 && innerSchema.Type[0] == "object" && (!oc.TypedMaps || !isTypedMapSchema(innerSchema)) {
 		refName = utils.ToPascalCase(refName)
 		ndcType = schema.NewNamedType(refName)
 		typeSchema = &rest.TypeSchema{Type: refName}
 	} else {
 		if innerSchema.Title != "" && !strings.Contains(innerSchema.Title, " ") {
 			fieldPaths = []string{utils.ToPascalCase(innerSchema.Title)}
+		} else if refName != "" && oc.TypedMaps && isTypedMapSchema(innerSchema) {
+			// entry objects of referenced maps are named by the definition
+			fieldPaths = []string{refName}
 		}
-		ndcType, typeSchema, err = oc.getSchemaType(innerSchema, apiPath, fieldPaths)
+		ndcType, typeSchema, err = oc.getSchemaType(innerSchema, apiPath, fieldPaths, pointer)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -248,16 +341,18 @@ func (oc *OAS2Builder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nul
 }
 
 // get and convert an OpenAPI data type to a NDC type from parameter
-func (oc *OAS2Builder) getSchemaTypeFromParameter(param *v2.Parameter, apiPath string, fieldPaths []string) (schema.TypeEncoder, error) {
+func (oc *OAS2Builder) getSchemaTypeFromParameter(param *v2.Parameter, apiPath string, fieldPaths []string, pointer string) (schema.TypeEncoder, error) {
 
 	var result schema.TypeEncoder
 	if param.Type == "" {
 		if oc.Strict {
-			return nil, errParameterSchemaEmpty(fieldPaths)
+			return nil, errParameterSchemaEmpty(fieldPaths, pointer)
 		}
+		oc.report.addJSONFallback(fieldPaths, pointer, "parameter without type")
 		result = oc.buildScalarJSON()
 	} else if isPrimitiveScalar(param.Type) {
-		scalarName := getScalarFromType(oc.schema, []string{param.Type}, param.Format, param.Enum, oc.trimPathPrefix(apiPath), fieldPaths)
+		scalarName := getScalarFromType(oc.schema, oc.report, oc.ConvertOptions, []string{param.Type}, param.Format, param.Enum, param.Extensions, oc.trimPathPrefix(apiPath), fieldPaths)
+		oc.report.SourceMap.setEnumScalar(scalarName, len(param.Enum), newSourceLocation(pointer, getParameterNodeV2(param)))
 		result = schema.NewNamedType(scalarName)
 	} else {
 		switch param.Type {
@@ -266,15 +361,17 @@ func (oc *OAS2Builder) getSchemaTypeFromParameter(param *v2.Parameter, apiPath s
 		case "array":
 			if param.Items == nil && param.Items.Type == "" {
 				if oc.Strict {
-					return nil, errors.New("array item is empty")
+					return nil, errArrayItemEmpty(fieldPaths, pointer)
 				}
+				oc.report.addJSONFallback(fieldPaths, buildJSONPointer(pointer, "items"), "array item without type")
 				result = schema.NewArrayType(oc.buildScalarJSON())
 			} else {
-				itemName := getScalarFromType(oc.schema, []string{param.Items.Type}, param.Format, param.Enum, oc.trimPathPrefix(apiPath), fieldPaths)
+				itemName := getScalarFromType(oc.schema, oc.report, oc.ConvertOptions, []string{param.Items.Type}, param.Format, param.Enum, param.Extensions, oc.trimPathPrefix(apiPath), fieldPaths)
+				oc.report.SourceMap.setEnumScalar(itemName, len(param.Enum), newSourceLocation(pointer, getParameterNodeV2(param)))
 				result = schema.NewArrayType(schema.NewNamedType(itemName))
 			}
 		default:
-			return nil, fmt.Errorf("unsupported schema type %s", param.Type)
+			return nil, errUnsupportedSchemaType(param.Type, pointer)
 		}
 	}
 
@@ -285,14 +382,33 @@ func (oc *OAS2Builder) getSchemaTypeFromParameter(param *v2.Parameter, apiPath s
 }
 
 // get and convert an OpenAPI data type to a NDC type
-func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
 
 	if typeSchema == nil {
-		return nil, nil, errParameterSchemaEmpty(fieldPaths)
+		return nil, nil, errParameterSchemaEmpty(fieldPaths, pointer)
+	}
+
+	if oc.TypedMaps && isTypedMapSchema(typeSchema) {
+		valueType, valueSchema, err := oc.getSchemaTypeFromProxy(typeSchema.AdditionalProperties.A, false, apiPath, append(fieldPaths, "value"), buildJSONPointer(pointer, "additionalProperties"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if valueType != nil {
+			result, typeResult := buildMapEntriesType(oc.schema, oc.typeUsageCounter, oc.report, typeSchema, valueType, valueSchema, fieldPaths, pointer)
+			return result, typeResult, nil
+		}
 	}
 
 	var typeResult *rest.TypeSchema
 	if len(typeSchema.AnyOf) > 0 || typeSchema.AdditionalProperties != nil || len(typeSchema.Type) > 1 {
+		switch {
+		case len(typeSchema.AnyOf) > 0:
+			oc.report.addJSONFallback(fieldPaths, pointer, "anyOf schema")
+		case typeSchema.AdditionalProperties != nil:
+			oc.report.addJSONFallback(fieldPaths, pointer, "object with additionalProperties")
+		default:
+			oc.report.addJSONFallback(fieldPaths, pointer, fmt.Sprintf("multiple types %v", typeSchema.Type))
+		}
 		scalarName := string(rest.ScalarJSON)
 		if _, ok := oc.schema.ScalarTypes[scalarName]; !ok {
 			oc.schema.ScalarTypes[scalarName] = *defaultScalarTypes[rest.ScalarJSON]
@@ -304,16 +420,19 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 	var result schema.TypeEncoder
 	if len(typeSchema.Type) == 0 {
 		if oc.Strict {
-			return nil, nil, errParameterSchemaEmpty(fieldPaths)
+			return nil, nil, errParameterSchemaEmpty(fieldPaths, pointer)
 		}
+		oc.report.addJSONFallback(fieldPaths, pointer, "schema without type")
 		result = oc.buildScalarJSON()
 		typeResult = createSchemaFromOpenAPISchema(typeSchema, string(rest.ScalarJSON))
 	} else {
 		typeName := typeSchema.Type[0]
 		if isPrimitiveScalar(typeName) {
-			scalarName := getScalarFromType(oc.schema, typeSchema.Type, typeSchema.Format, typeSchema.Enum, oc.trimPathPrefix(apiPath), fieldPaths)
+			scalarName := getScalarFromType(oc.schema, oc.report, oc.ConvertOptions, typeSchema.Type, typeSchema.Format, typeSchema.Enum, typeSchema.Extensions, oc.trimPathPrefix(apiPath), fieldPaths)
+			oc.report.SourceMap.setEnumScalar(scalarName, len(typeSchema.Enum), newSourceLocation(pointer, getSchemaNode(typeSchema)))
 			result = schema.NewNamedType(scalarName)
 			typeResult = createSchemaFromOpenAPISchema(typeSchema, scalarName)
+			typeResult.Representation = oc.getNumberRepresentation(scalarName, typeName)
 		} else {
 
 			typeResult = createSchemaFromOpenAPISchema(typeSchema, "")
@@ -324,6 +443,7 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 
 				if typeSchema.Properties == nil || typeSchema.Properties.IsZero() {
 					// treat no-property objects as a JSON scalar
+					oc.report.addJSONFallback(fieldPaths, pointer, "object without properties")
 					oc.schema.ScalarTypes[refName] = *defaultScalarTypes[rest.ScalarJSON]
 				} else {
 					object := schema.ObjectType{
@@ -334,10 +454,12 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 					}
 
 					typeResult.Properties = make(map[string]rest.TypeSchema)
+					fieldLocations := make(map[string]SourceLocation)
 					for prop := typeSchema.Properties.First(); prop != nil; prop = prop.Next() {
 						propName := prop.Key()
 						nullable := !slices.Contains(typeSchema.Required, propName)
-						propType, propApiSchema, err := oc.getSchemaTypeFromProxy(prop.Value(), nullable, apiPath, append(fieldPaths, propName))
+						propPointer := buildJSONPointer(pointer, "properties", propName)
+						propType, propApiSchema, err := oc.getSchemaTypeFromProxy(prop.Value(), nullable, apiPath, append(fieldPaths, propName), propPointer)
 						if err != nil {
 							return nil, nil, err
 						}
@@ -350,18 +472,21 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 						propApiSchema.Nullable = nullable
 						typeResult.Properties[propName] = *propApiSchema
 						object.Fields[propName] = objField
+						fieldLocations[propName] = newSourceLocation(propPointer, getSchemaProxyNode(prop.Value()))
 
 						oc.typeUsageCounter.Add(getNamedType(propType, true, ""), 1)
 					}
 
 					oc.schema.ObjectTypes[refName] = object
+					oc.report.SourceMap.setObjectType(refName, newSourceLocation(pointer, getSchemaNode(typeSchema)), fieldLocations)
 				}
 				result = schema.NewNamedType(refName)
 			case "array":
 				if typeSchema.Items == nil || typeSchema.Items.A == nil {
 					if oc.ConvertOptions.Strict {
-						return nil, nil, errors.New("array item is empty")
+						return nil, nil, errArrayItemEmpty(fieldPaths, pointer)
 					}
+					oc.report.addJSONFallback(fieldPaths, buildJSONPointer(pointer, "items"), "array item without type")
 					result = schema.NewArrayType(oc.buildScalarJSON())
 				} else {
 					itemName := getSchemaRefTypeNameV2(typeSchema.Items.A.GetReference())
@@ -371,7 +496,7 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 					} else {
 						itemSchemaA := typeSchema.Items.A.Schema()
 						if itemSchemaA != nil {
-							itemSchema, propType, err := oc.getSchemaType(itemSchemaA, apiPath, fieldPaths)
+							itemSchema, propType, err := oc.getSchemaType(itemSchemaA, apiPath, fieldPaths, buildJSONPointer(pointer, "items"))
 							if err != nil {
 								return nil, nil, err
 							}
@@ -387,7 +512,7 @@ func (oc *OAS2Builder) getSchemaType(typeSchema *base.Schema, apiPath string, fi
 				}
 
 			default:
-				return nil, nil, fmt.Errorf("unsupported schema type %s", typeName)
+				return nil, nil, errUnsupportedSchemaType(typeName, pointer)
 			}
 		}
 	}
@@ -407,7 +532,7 @@ func (oc *OAS2Builder) convertComponentSchemas(schemaItem orderedmap.Pair[string
 	if typeSchema == nil || !slices.Contains(typeSchema.Type, "object") {
 		return nil
 	}
-	_, _, err := oc.getSchemaType(typeSchema, "", []string{typeKey})
+	_, _, err := oc.getSchemaType(typeSchema, "", []string{typeKey}, buildJSONPointer("", "definitions", typeKey))
 	return err
 }
 
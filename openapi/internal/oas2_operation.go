@@ -11,12 +11,18 @@ import (
 	"github.com/hasura/ndc-rest-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
 	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
 )
 
 type oas2OperationBuilder struct {
 	builder       *OAS2Builder
 	Arguments     map[string]schema.ArgumentInfo
 	RequestParams []rest.RequestParameter
+	// the response metadata of the operation
+	response rest.Response
+	// the reason if the operation can't be converted
+	skipReason string
 }
 
 func newOAS2OperationBuilder(builder *OAS2Builder) *oas2OperationBuilder {
@@ -27,14 +33,14 @@ func newOAS2OperationBuilder(builder *OAS2Builder) *oas2OperationBuilder {
 }
 
 // BuildFunction build a REST NDC function information from OpenAPI v2 operation
-func (oc *oas2OperationBuilder) BuildFunction(pathKey string, operation *v2.Operation) (*rest.RESTFunctionInfo, error) {
+func (oc *oas2OperationBuilder) BuildFunction(pathKey string, method string, operation *v2.Operation) (*rest.RESTFunctionInfo, error) {
 
 	if operation == nil {
 		return nil, nil
 	}
 	funcName := operation.OperationId
 	if funcName == "" {
-		funcName = buildPathMethodName(pathKey, "get", oc.builder.ConvertOptions)
+		funcName = buildPathMethodName(pathKey, method, oc.builder.ConvertOptions)
 	}
 	if oc.builder.Prefix != "" {
 		funcName = utils.StringSliceToCamelCase([]string{oc.builder.Prefix, funcName})
@@ -42,6 +48,7 @@ func (oc *oas2OperationBuilder) BuildFunction(pathKey string, operation *v2.Oper
 	oc.builder.Logger.Info("function",
 		slog.String("name", funcName),
 		slog.String("path", pathKey),
+		slog.String("method", method),
 	)
 
 	responseContentType := oc.getResponseContentTypeV2(operation.Produces)
@@ -49,21 +56,25 @@ func (oc *oas2OperationBuilder) BuildFunction(pathKey string, operation *v2.Oper
 		oc.builder.Logger.Info("supported response content type",
 			slog.String("name", funcName),
 			slog.String("path", pathKey),
-			slog.String("method", "get"),
+			slog.String("method", method),
 			slog.Any("produces", operation.Produces),
 			slog.Any("consumes", operation.Consumes),
 		)
+		oc.builder.report.addSkippedOperation(pathKey, method, operation.OperationId, oc.getUnsupportedContentTypeReason(operation.Produces))
 		return nil, nil
 	}
 
-	resultType, err := oc.convertResponse(operation.Responses, pathKey, []string{funcName, "Result"})
+	oc.response.ContentType = responseContentType
+	operationPointer := buildJSONPointer("", "paths", pathKey, method)
+	resultType, err := oc.convertResponse(operation.Responses, method, pathKey, []string{funcName, "Result"}, operationPointer)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s", pathKey, err)
 	}
 	if resultType == nil {
+		oc.builder.report.addSkippedOperation(pathKey, method, operation.OperationId, oc.skipReason)
 		return nil, nil
 	}
-	reqBody, err := oc.convertParameters(operation, pathKey, []string{funcName})
+	reqBody, err := oc.convertParameters(operation, pathKey, []string{funcName}, operationPointer)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s", funcName, err)
 	}
@@ -71,13 +82,11 @@ func (oc *oas2OperationBuilder) BuildFunction(pathKey string, operation *v2.Oper
 	function := rest.RESTFunctionInfo{
 		Request: &rest.Request{
 			URL:         pathKey,
-			Method:      "get",
+			Method:      method,
 			Parameters:  oc.RequestParams,
 			RequestBody: reqBody,
-			Response: rest.Response{
-				ContentType: responseContentType,
-			},
-			Security: convertSecurities(operation.Security),
+			Response:    oc.response,
+			Security:    convertSecurities(operation.Security),
 		},
 		FunctionInfo: schema.FunctionInfo{
 			Name:       funcName,
@@ -123,19 +132,23 @@ func (oc *oas2OperationBuilder) BuildProcedure(pathKey string, method string, op
 			slog.Any("produces", operation.Produces),
 			slog.Any("consumes", operation.Consumes),
 		)
+		oc.builder.report.addSkippedOperation(pathKey, method, operation.OperationId, oc.getUnsupportedContentTypeReason(operation.Produces))
 		return nil, nil
 	}
 
-	resultType, err := oc.convertResponse(operation.Responses, pathKey, []string{procName, "Result"})
+	oc.response.ContentType = responseContentType
+	operationPointer := buildJSONPointer("", "paths", pathKey, method)
+	resultType, err := oc.convertResponse(operation.Responses, method, pathKey, []string{procName, "Result"}, operationPointer)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s", pathKey, err)
 	}
 
 	if resultType == nil {
+		oc.builder.report.addSkippedOperation(pathKey, method, operation.OperationId, oc.skipReason)
 		return nil, nil
 	}
 
-	reqBody, err := oc.convertParameters(operation, pathKey, []string{procName})
+	reqBody, err := oc.convertParameters(operation, pathKey, []string{procName}, operationPointer)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s", pathKey, err)
 	}
@@ -147,9 +160,7 @@ func (oc *oas2OperationBuilder) BuildProcedure(pathKey string, method string, op
 			Parameters:  oc.RequestParams,
 			RequestBody: reqBody,
 			Security:    convertSecurities(operation.Security),
-			Response: rest.Response{
-				ContentType: responseContentType,
-			},
+			Response:    oc.response,
 		},
 		ProcedureInfo: schema.ProcedureInfo{
 			Name:       procName,
@@ -165,7 +176,7 @@ func (oc *oas2OperationBuilder) BuildProcedure(pathKey string, method string, op
 	return &procedure, nil
 }
 
-func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPath string, fieldPaths []string) (*rest.RequestBody, error) {
+func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPath string, fieldPaths []string, operationPointer string) (*rest.RequestBody, error) {
 
 	if operation == nil || len(operation.Parameters) == 0 {
 		return nil, nil
@@ -184,10 +195,12 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 	formDataObject := schema.ObjectType{
 		Fields: schema.ObjectTypeFields{},
 	}
-	for _, param := range operation.Parameters {
+	formDataLocations := map[string]SourceLocation{}
+	for i, param := range operation.Parameters {
 		if param == nil {
 			continue
 		}
+		paramPointer := buildJSONPointer(operationPointer, "parameters", fmt.Sprint(i))
 		paramName := param.Name
 		if paramName == "" {
 			return nil, errors.New("parameter name is empty")
@@ -203,23 +216,38 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 		}
 
 		if param.Type != "" {
-			typeEncoder, err = oc.builder.getSchemaTypeFromParameter(param, apiPath, fieldPaths)
+			typeEncoder, err = oc.builder.getSchemaTypeFromParameter(param, apiPath, fieldPaths, paramPointer)
 			if err != nil {
 				return nil, err
 			}
 			nullable := !paramRequired
 			typeSchema = &rest.TypeSchema{
-				Type:     getNamedType(typeEncoder, false, param.Type),
-				Pattern:  param.Pattern,
-				Nullable: nullable,
+				Type:        getNamedType(typeEncoder, false, param.Type),
+				Format:      param.Format,
+				Pattern:     param.Pattern,
+				Nullable:    nullable,
+				Enum:        decodeEnumNodes(param.Enum, param.Type),
+				Default:     decodeNodeValue(param.Default),
+				UniqueItems: param.UniqueItems != nil && *param.UniqueItems,
 			}
+			typeSchema.Representation = oc.builder.getNumberRepresentation(typeSchema.Type, param.Type)
 			if param.Maximum != nil {
 				maximum := float64(*param.Maximum)
 				typeSchema.Maximum = &maximum
+				if param.ExclusiveMaximum != nil && *param.ExclusiveMaximum {
+					typeSchema.ExclusiveMaximum = &maximum
+				}
 			}
 			if param.Minimum != nil {
 				minimum := float64(*param.Minimum)
 				typeSchema.Minimum = &minimum
+				if param.ExclusiveMinimum != nil && *param.ExclusiveMinimum {
+					typeSchema.ExclusiveMinimum = &minimum
+				}
+			}
+			if param.MultipleOf != nil {
+				multipleOf := float64(*param.MultipleOf)
+				typeSchema.MultipleOf = &multipleOf
 			}
 			if param.MaxLength != nil {
 				maxLength := int64(*param.MaxLength)
@@ -229,8 +257,16 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 				minLength := int64(*param.MinLength)
 				typeSchema.MinLength = &minLength
 			}
+			if param.MaxItems != nil {
+				maxItems := int64(*param.MaxItems)
+				typeSchema.MaxItems = &maxItems
+			}
+			if param.MinItems != nil {
+				minItems := int64(*param.MinItems)
+				typeSchema.MinItems = &minItems
+			}
 		} else if param.Schema != nil {
-			typeEncoder, typeSchema, err = oc.builder.getSchemaTypeFromProxy(param.Schema, !paramRequired, apiPath, fieldPaths)
+			typeEncoder, typeSchema, err = oc.builder.getSchemaTypeFromProxy(param.Schema, !paramRequired, apiPath, fieldPaths, buildJSONPointer(paramPointer, "schema"))
 			if err != nil {
 				return nil, err
 			}
@@ -264,6 +300,7 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 					Description: argument.Description,
 				}
 				formData.Properties[paramName] = *typeSchema
+				formDataLocations[paramName] = newSourceLocation(paramPointer, getParameterNodeV2(param))
 			}
 		default:
 			oc.Arguments[paramName] = argument
@@ -278,6 +315,7 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 	if len(formData.Properties) > 0 {
 		bodyName := fmt.Sprintf("%sBody", utils.StringSliceToPascalCase(fieldPaths))
 		oc.builder.schema.ObjectTypes[bodyName] = formDataObject
+		oc.builder.report.SourceMap.setObjectType(bodyName, newSourceLocation(buildJSONPointer(operationPointer, "parameters"), nil), formDataLocations)
 		oc.builder.typeUsageCounter.Add(bodyName, 1)
 
 		desc := fmt.Sprintf("Form data of %s", apiPath)
@@ -295,12 +333,14 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, apiPa
 
 }
 
-func (oc *oas2OperationBuilder) convertResponse(responses *v2.Responses, apiPath string, fieldPaths []string) (schema.TypeEncoder, error) {
+func (oc *oas2OperationBuilder) convertResponse(responses *v2.Responses, method string, apiPath string, fieldPaths []string, operationPointer string) (schema.TypeEncoder, error) {
 	if responses == nil || responses.Codes == nil || responses.Codes.IsZero() {
+		oc.skipReason = "no response status code"
 		return nil, nil
 	}
 
 	var resp *v2.Response
+	statusCode := "default"
 	if responses.Codes == nil || responses.Codes.IsZero() {
 		// the response is always successful
 		resp = responses.Default
@@ -315,27 +355,208 @@ func (oc *oas2OperationBuilder) convertResponse(responses *v2.Responses, apiPath
 			}
 
 			if isUnsupportedResponseCodes(code) {
+				oc.skipReason = fmt.Sprintf("unsupported response status code %d", code)
 				return nil, nil
 			} else if code >= 200 && code < 300 {
 				resp = r.Value()
+				statusCode = r.Key()
 				break
 			}
 		}
 	}
+	responsePointer := buildJSONPointer(operationPointer, "responses", statusCode)
+
+	// responses of extra methods such as HEAD usually don't have the body.
+	// The result is the object of response headers if they are documented
+	if resp != nil && slices.Contains(extraMethods, method) && (resp.Schema == nil || method == "head") {
+		headersType, err := oc.convertResponseHeaders(resp.Headers, apiPath, fieldPaths, buildJSONPointer(responsePointer, "headers"))
+		if err != nil {
+			return nil, err
+		}
+		if headersType != nil {
+			return headersType, nil
+		}
+		resp = nil
+	}
+
+	resultType, typeSchema, err := oc.convertResponseBody(resp, apiPath, fieldPaths, responsePointer)
+	if err != nil || resp == nil {
+		return resultType, err
+	}
+	if oc.builder.StatusCodeResponses && responses.Codes != nil {
+		resultType, err = oc.convertStatusCodeResponses(responses, resultType, typeSchema, statusCode, apiPath, fieldPaths, operationPointer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !oc.builder.ResponseHeaders {
+		return resultType, nil
+	}
+
+	// wrap the result in the envelope object with response headers
+	headersType, err := oc.convertResponseHeaders(resp.Headers, apiPath, append(slices.Clone(fieldPaths), "Headers"), buildJSONPointer(responsePointer, "headers"))
+	if err != nil || headersType == nil {
+		return resultType, err
+	}
+	oc.response.Envelope = true
+	return buildResponseEnvelope(oc.builder.schema, oc.builder.typeUsageCounter, resultType, headersType, fieldPaths), nil
+}
+
+// convert responses of all documented status codes. Success responses with different types are merged into a union object type
+// and error payloads are converted to object types
+func (oc *oas2OperationBuilder) convertStatusCodeResponses(responses *v2.Responses, resultType schema.TypeEncoder, typeSchema *rest.TypeSchema, statusCode string, apiPath string, fieldPaths []string, operationPointer string) (schema.TypeEncoder, error) {
+	statusCodes := map[string]rest.ResponseContent{}
+	var members []responseUnionMember
+	hasBodyless := typeSchema == nil
+	if hasBodyless {
+		statusCodes[statusCode] = rest.ResponseContent{}
+	} else {
+		statusCodes[statusCode] = rest.ResponseContent{
+			ContentType: oc.response.ContentType,
+			Schema:      typeSchema,
+			Type:        resultType.Encode(),
+		}
+		members = append(members, responseUnionMember{StatusCode: statusCode, Type: resultType})
+	}
+
+	errorPaths := append(slices.Clone(fieldPaths[:len(fieldPaths)-1]), "Error")
+	convertResponse := func(code string, resp *v2.Response) error {
+		if resp.Schema == nil {
+			statusCodes[code] = rest.ResponseContent{}
+			hasBodyless = hasBodyless || isSuccessStatusCode(code)
+			return nil
+		}
+		bodyPaths := append(slices.Clone(fieldPaths), code)
+		if isErrorStatusCode(code) {
+			bodyPaths = append(slices.Clone(errorPaths), code)
+		}
+		bodyType, bodySchema, err := oc.convertResponseBody(resp, apiPath, bodyPaths, buildJSONPointer(operationPointer, "responses", code))
+		if err != nil {
+			return err
+		}
+		statusCodes[code] = rest.ResponseContent{
+			ContentType: oc.response.ContentType,
+			Schema:      bodySchema,
+			Type:        bodyType.Encode(),
+		}
+		if isSuccessStatusCode(code) {
+			members = append(members, responseUnionMember{StatusCode: code, Type: bodyType})
+		}
+		return nil
+	}
 
-	// return nullable boolean type if the response content is null
+	for r := responses.Codes.First(); r != nil; r = r.Next() {
+		code := r.Key()
+		if code == statusCode || r.Value() == nil || (!isSuccessStatusCode(code) && !isErrorStatusCode(code)) {
+			continue
+		}
+		if err := convertResponse(code, r.Value()); err != nil {
+			return nil, err
+		}
+	}
+	if responses.Default != nil {
+		if err := convertResponse("default", responses.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	oc.response.StatusCodes = statusCodes
+	if unionType := buildResponseUnion(oc.builder.schema, oc.builder.typeUsageCounter, members, hasBodyless, statusCodes, fieldPaths); unionType != nil {
+		return unionType, nil
+	}
+	return resultType, nil
+}
+
+// convert the response body to the result type. Returns the nullable boolean type if the response content is null
+func (oc *oas2OperationBuilder) convertResponseBody(resp *v2.Response, apiPath string, fieldPaths []string, responsePointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
 	if resp == nil || resp.Schema == nil {
 		scalarName := string(rest.ScalarBoolean)
+		if _, ok := oc.builder.schema.ScalarTypes[scalarName]; !ok {
+			oc.builder.schema.ScalarTypes[scalarName] = *defaultScalarTypes[rest.ScalarBoolean]
+		}
 		oc.builder.typeUsageCounter.Add(scalarName, 1)
-		return schema.NewNullableNamedType(scalarName), nil
+		return schema.NewNullableNamedType(scalarName), nil, nil
 	}
 
-	schemaType, _, err := oc.builder.getSchemaTypeFromProxy(resp.Schema, false, apiPath, fieldPaths)
+	schemaType, typeSchema, err := oc.builder.getSchemaTypeFromProxy(resp.Schema, false, apiPath, fieldPaths, buildJSONPointer(responsePointer, "schema"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	oc.builder.typeUsageCounter.Add(getNamedType(schemaType, true, ""), 1)
-	return schemaType, nil
+	return schemaType, typeSchema, nil
+}
+
+// convert response headers to fields of an object type. Returns nil if the response doesn't have any header
+func (oc *oas2OperationBuilder) convertResponseHeaders(headers *orderedmap.Map[string, *v2.Header], apiPath string, fieldPaths []string, pointer string) (schema.TypeEncoder, error) {
+	if headers == nil || headers.IsZero() {
+		return nil, nil
+	}
+
+	object := schema.ObjectType{
+		Fields: make(schema.ObjectTypeFields),
+	}
+	fieldLocations := make(map[string]SourceLocation)
+	for pair := headers.First(); pair != nil; pair = pair.Next() {
+		headerName := pair.Key()
+		header := pair.Value()
+		if header == nil {
+			continue
+		}
+		fieldName := utils.ToCamelCase(headerName)
+		headerPointer := buildJSONPointer(pointer, headerName)
+		headerType := header.Type
+		if headerType == "" {
+			// header values are strings if the type is empty
+			headerType = "string"
+		}
+		// Swagger 2.0 headers share the same data types with parameters
+		param := &v2.Parameter{
+			Name:    headerName,
+			In:      string(rest.InHeader),
+			Type:    headerType,
+			Format:  header.Format,
+			Items:   header.Items,
+			Pattern: header.Pattern,
+		}
+		for _, value := range header.Enum {
+			node := &yaml.Node{}
+			if err := node.Encode(value); err != nil {
+				return nil, err
+			}
+			param.Enum = append(param.Enum, node)
+		}
+		typeEncoder, err := oc.builder.getSchemaTypeFromParameter(param, apiPath, append(fieldPaths, fieldName), headerPointer)
+		if err != nil {
+			return nil, err
+		}
+		oc.builder.typeUsageCounter.Add(getNamedType(typeEncoder, true, ""), 1)
+
+		field := schema.ObjectField{
+			Type: typeEncoder.Encode(),
+		}
+		if header.Description != "" {
+			field.Description = &header.Description
+		}
+		object.Fields[fieldName] = field
+		if low := header.GoLow(); low != nil {
+			fieldLocations[fieldName] = newSourceLocation(headerPointer, low.Type.KeyNode)
+		}
+		oc.response.Headers = append(oc.response.Headers, rest.ResponseHeader{
+			Name:      headerName,
+			FieldName: fieldName,
+			Schema: &rest.TypeSchema{
+				Type:     getNamedType(typeEncoder, false, headerType),
+				Pattern:  header.Pattern,
+				Nullable: true,
+			},
+		})
+	}
+
+	objectName := utils.StringSliceToPascalCase(fieldPaths)
+	oc.builder.schema.ObjectTypes[objectName] = object
+	oc.builder.report.SourceMap.setObjectType(objectName, newSourceLocation(pointer, nil), fieldLocations)
+	oc.builder.typeUsageCounter.Add(objectName, 1)
+	return schema.NewNamedType(objectName), nil
 }
 
 func (oc *oas2OperationBuilder) getResponseContentTypeV2(contentTypes []string) string {
@@ -354,3 +575,7 @@ func (oc *oas2OperationBuilder) getResponseContentTypeV2(contentTypes []string)
 	}
 	return ""
 }
+
+func (oc *oas2OperationBuilder) getUnsupportedContentTypeReason(contentTypes []string) string {
+	return fmt.Sprintf("response content types %v are not in allowed content types %v", contentTypes, oc.builder.ConvertOptions.AllowedContentTypes)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
 )
 
 // OAS3Builder the NDC schema builder from OpenAPI 3.0 specification
@@ -25,12 +26,22 @@ type OAS3Builder struct {
 	// This cache temporarily stores them to avoid infinite recursive reference.
 	schemaCache      map[string]SchemaInfoCache
 	typeUsageCounter TypeUsageCounter
+	report           *ConversionReport
+	// errors of skipped operations in tolerant mode
+	operationErrors []error
+	// functions of GET operations that may be converted to collections
+	collectionCandidates []collectionCandidate
+	// variants of object types that are merged from discriminated unions,
+	// so references of these types keep the variant information
+	discriminators map[string]*rest.Discriminator
 }
 
 // SchemaInfoCache stores prebuilt information of component schema types.
 type SchemaInfoCache struct {
 	Name   string
 	Schema schema.TypeEncoder
+	// the type schema of typed maps that are converted to arrays of entries
+	TypeSchema *rest.TypeSchema
 }
 
 // NewOAS3Builder creates an OAS3Builder instance
@@ -39,9 +50,14 @@ func NewOAS3Builder(schema *rest.NDCRestSchema, options ConvertOptions) *OAS3Bui
 		schema:           schema,
 		schemaCache:      make(map[string]SchemaInfoCache),
 		typeUsageCounter: TypeUsageCounter{},
+		report:           NewConversionReport(),
+		discriminators:   make(map[string]*rest.Discriminator),
 		ConvertOptions:   applyConvertOptions(options),
 	}
 
+	if builder.ConvertOptions.SourceMap {
+		builder.report.SourceMap = NewSourceMap()
+	}
 	setDefaultSettings(builder.schema.Settings, builder.ConvertOptions)
 	return builder
 }
@@ -51,7 +67,35 @@ func (oc *OAS3Builder) Schema() *rest.NDCRestSchema {
 	return oc.schema
 }
 
+// Report returns the conversion report
+func (oc *OAS3Builder) Report() *ConversionReport {
+	return oc.report
+}
+
+// OperationErrors returns errors of operations that are skipped in tolerant mode
+func (oc *OAS3Builder) OperationErrors() []error {
+	return oc.operationErrors
+}
+
 func (oc *OAS3Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v3.Document]) error {
+	if err := utils.ValidateOperationFilters(oc.Include, oc.Exclude); err != nil {
+		return err
+	}
+	if err := utils.ValidateCollectionRules(oc.Collections); err != nil {
+		return err
+	}
+	if err := utils.ValidateOperationKindRules(oc.OperationKinds); err != nil {
+		return err
+	}
+	if err := utils.ValidateScalarMappings(oc.ScalarMappings); err != nil {
+		return err
+	}
+	if err := validateNumberRepresentations(oc.ConvertOptions); err != nil {
+		return err
+	}
+	if err := validateExtraMethods(oc.ExtraMethods); err != nil {
+		return err
+	}
 
 	if docModel.Model.Info != nil {
 		oc.schema.Settings.Version = docModel.Model.Info.Version
@@ -66,11 +110,14 @@ func (oc *OAS3Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v3.
 			}
 		}
 	}
-	for iterPath := docModel.Model.Paths.PathItems.First(); iterPath != nil; iterPath = iterPath.Next() {
-		if err := oc.pathToNDCOperations(iterPath); err != nil {
-			return err
+	if docModel.Model.Paths != nil {
+		for iterPath := docModel.Model.Paths.PathItems.First(); iterPath != nil; iterPath = iterPath.Next() {
+			if err := oc.pathToNDCOperations(iterPath); err != nil {
+				return err
+			}
 		}
 	}
+	oc.reportWebhooks(docModel.Model.Webhooks)
 
 	if docModel.Model.Components.SecuritySchemes != nil {
 		oc.schema.Settings.SecuritySchemes = make(map[string]rest.SecurityScheme)
@@ -83,10 +130,15 @@ func (oc *OAS3Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v3.
 	}
 	oc.schema.Settings.Security = convertSecurities(docModel.Model.Security)
 
+	if err := oc.convertCollections(); err != nil {
+		return err
+	}
+
 	// reevaluate write argument types
 	oc.schemaCache = make(map[string]SchemaInfoCache)
 	oc.transformWriteSchema()
 	cleanUnusedSchemaTypes(oc.schema, &oc.typeUsageCounter)
+	oc.report.SourceMap.prune(oc.schema)
 
 	return nil
 }
@@ -199,50 +251,94 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 	pathKey := pathItem.Key()
 	pathValue := pathItem.Value()
 
-	if pathValue.Get != nil {
-		funcGet, err := newOAS3OperationBuilder(oc, pathKey, "get").BuildFunction(pathValue.Get)
-		if err != nil {
-			return err
+	for _, item := range []struct {
+		method    string
+		operation *v3.Operation
+	}{
+		{"get", pathValue.Get},
+		{"post", pathValue.Post},
+		{"put", pathValue.Put},
+		{"patch", pathValue.Patch},
+		{"delete", pathValue.Delete},
+		{"head", pathValue.Head},
+		{"options", pathValue.Options},
+		{"trace", pathValue.Trace},
+	} {
+		if !isMethodEnabled(oc.ConvertOptions, item.method) || item.operation == nil {
+			continue
 		}
-		if funcGet != nil {
-			oc.schema.Functions = append(oc.schema.Functions, funcGet)
+		filterInput := utils.OperationFilterInput{
+			Path:        pathKey,
+			Method:      item.method,
+			OperationID: item.operation.OperationId,
+			Tags:        item.operation.Tags,
+		}
+		if !isOperationIncluded(oc.ConvertOptions, oc.report, filterInput) {
+			continue
+		}
+		kind, err := getOperationKind(oc.ConvertOptions, getExtensionNode(item.operation.Extensions, operationKindExtension), filterInput)
+		if err == nil && kind == rest.OperationFunction {
+			var function *rest.RESTFunctionInfo
+			function, err = newOAS3OperationBuilder(oc, pathKey, item.method).BuildFunction(item.operation)
+			if err == nil && function != nil {
+				err = oc.addFunction(pathKey, item.method, item.operation, function, item.operation.GoLow().KeyNode)
+			}
+		} else if err == nil {
+			var procedure *rest.RESTProcedureInfo
+			procedure, err = newOAS3OperationBuilder(oc, pathKey, item.method).BuildProcedure(item.operation)
+			if err == nil && procedure != nil {
+				oc.schema.Procedures = append(oc.schema.Procedures, procedure)
+				oc.report.SourceMap.setProcedure(procedure.Name, newSourceLocation(buildJSONPointer("", "paths", pathKey, item.method), item.operation.GoLow().KeyNode))
+			}
+		}
+		if err != nil {
+			if err := handleOperationError(oc.ConvertOptions, oc.report, &oc.operationErrors, pathKey, item.method, item.operation.OperationId, err); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
 
-	procPost, err := newOAS3OperationBuilder(oc, pathKey, "post").BuildProcedure(pathValue.Post)
-	if err != nil {
-		return err
-	}
-	if procPost != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPost)
-	}
-
-	procPut, err := newOAS3OperationBuilder(oc, pathKey, "put").BuildProcedure(pathValue.Put)
-	if err != nil {
-		return err
-	}
-	if procPut != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPut)
-	}
-
-	procPatch, err := newOAS3OperationBuilder(oc, pathKey, "patch").BuildProcedure(pathValue.Patch)
-	if err != nil {
-		return err
-	}
-	if procPatch != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procPatch)
+// add the function to the schema. The function of a GET operation is also added to collection candidates
+// if the operation is declared or detected as a list endpoint
+func (oc *OAS3Builder) addFunction(pathKey string, method string, operation *v3.Operation, fn *rest.RESTFunctionInfo, keyNode *yaml.Node) error {
+	var settings *collectionSettings
+	if method == "get" {
+		var err error
+		settings, err = getCollectionSettings(oc.ConvertOptions, getExtensionNode(operation.Extensions, collectionExtension), utils.OperationFilterInput{
+			Path:        pathKey,
+			Method:      method,
+			OperationID: operation.OperationId,
+			Tags:        operation.Tags,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	procDelete, err := newOAS3OperationBuilder(oc, pathKey, "delete").BuildProcedure(pathValue.Delete)
-	if err != nil {
-		return err
-	}
-	if procDelete != nil {
-		oc.schema.Procedures = append(oc.schema.Procedures, procDelete)
+	location := newSourceLocation(buildJSONPointer("", "paths", pathKey, method), keyNode)
+	oc.schema.Functions = append(oc.schema.Functions, fn)
+	oc.report.SourceMap.setFunction(fn.Name, location)
+	if settings != nil {
+		oc.collectionCandidates = append(oc.collectionCandidates, collectionCandidate{
+			PathKey:     pathKey,
+			OperationID: operation.OperationId,
+			Location:    location,
+			Function:    fn,
+			Settings:    settings,
+		})
 	}
 	return nil
 }
 
+// convert candidate functions of list endpoints to collections
+func (oc *OAS3Builder) convertCollections() error {
+	return applyCollectionCandidates(oc.schema, oc.ConvertOptions, oc.report.SourceMap, oc.collectionCandidates, func(candidate collectionCandidate, err error) error {
+		return handleOperationError(oc.ConvertOptions, oc.report, &oc.operationErrors, candidate.PathKey, "get", candidate.OperationID, err)
+	})
+}
+
 func (oc *OAS3Builder) convertComponentSchemas(schemaItem orderedmap.Pair[string, *base.SchemaProxy]) error {
 	typeValue := schemaItem.Value()
 	typeSchema := typeValue.Schema()
@@ -257,7 +353,7 @@ func (oc *OAS3Builder) convertComponentSchemas(schemaItem orderedmap.Pair[string
 		return nil
 	}
 	typeEncoder, _, _, err := newOAS3SchemaBuilder(oc, "", rest.InBody, false).
-		getSchemaType(typeSchema, []string{typeKey})
+		getSchemaType(typeSchema, []string{typeKey}, buildJSONPointer("", "components", "schemas", typeKey))
 	if err != nil {
 		return err
 	}
@@ -296,6 +392,15 @@ func (oc *OAS3Builder) buildScalarJSON() *schema.NamedType {
 // transform and reassign write object types to arguments
 func (oc *OAS3Builder) transformWriteSchema() {
 
+	for _, collection := range oc.schema.Collections {
+		for key, arg := range collection.Arguments {
+			ty, name, _ := oc.populateWriteSchemaType(arg.Type)
+			if name != "" {
+				arg.Type = ty
+				collection.Arguments[key] = arg
+			}
+		}
+	}
 	for _, fn := range oc.schema.Functions {
 		for key, arg := range fn.Arguments {
 			ty, name, _ := oc.populateWriteSchemaType(arg.Type)
@@ -377,6 +482,7 @@ func (oc *OAS3Builder) populateWriteSchemaType(schemaType schema.Type) (schema.T
 			oc.typeUsageCounter.Add(writeName, 1)
 			oc.typeUsageCounter.Add(ty.Name, -1)
 			oc.schema.ObjectTypes[writeName] = writeObject
+			oc.report.SourceMap.copyObjectType(ty.Name, writeName)
 			return schema.NewNamedType(writeName).Encode(), writeName, true
 		}
 		return schemaType, ty.Name, false
@@ -407,3 +513,23 @@ func convertV3OAuthFLow(input *v3.OAuthFlow) *rest.OAuthFlow {
 
 	return result
 }
+
+// webhooks of OpenAPI 3.1 documents are incoming requests that the API sends to consumers.
+// They can't be converted to NDC operations, so they are reported as skipped operations
+func (oc *OAS3Builder) reportWebhooks(webhooks *orderedmap.Map[string, *v3.PathItem]) {
+	if webhooks == nil {
+		return
+	}
+	for hook := webhooks.First(); hook != nil; hook = hook.Next() {
+		if hook.Value() == nil {
+			continue
+		}
+		for op := hook.Value().GetOperations().First(); op != nil; op = op.Next() {
+			var operationID string
+			if op.Value() != nil {
+				operationID = op.Value().OperationId
+			}
+			oc.report.addSkippedWebhook(hook.Key(), op.Key(), operationID)
+		}
+	}
+}
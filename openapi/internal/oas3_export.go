@@ -0,0 +1,895 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultExportTitle       = "NDC REST API"
+	defaultExportVersion     = "1.0.0"
+	exportResponseCode       = "200"
+	exportResponseDesc       = "Successful response"
+	componentSchemaRefPrefix = "#/components/schemas/"
+)
+
+// OAS3Exporter the OpenAPI 3.0 document builder from NDC REST schema
+type OAS3Exporter struct {
+	*ExportOptions
+
+	schema   *rest.NDCRestSchema
+	document *v3.Document
+}
+
+// NewOAS3Exporter creates an OAS3Exporter instance
+func NewOAS3Exporter(schema *rest.NDCRestSchema, options ExportOptions) *OAS3Exporter {
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	if options.Title == "" {
+		options.Title = defaultExportTitle
+	}
+	return &OAS3Exporter{
+		ExportOptions: &options,
+		schema:        schema,
+	}
+}
+
+// Build converts the NDC REST schema to the OpenAPI 3.0 document model
+func (oe *OAS3Exporter) Build() (*v3.Document, error) {
+	oe.document = &v3.Document{
+		Version: "3.0.3",
+		Info: &base.Info{
+			Title:   oe.Title,
+			Version: defaultExportVersion,
+		},
+		Paths: &v3.Paths{
+			PathItems: orderedmap.New[string, *v3.PathItem](),
+		},
+		Components: &v3.Components{
+			Schemas:         orderedmap.New[string, *base.SchemaProxy](),
+			SecuritySchemes: orderedmap.New[string, *v3.SecurityScheme](),
+		},
+	}
+
+	if oe.schema.Settings != nil {
+		if err := oe.exportSettings(oe.schema.Settings); err != nil {
+			return nil, err
+		}
+	}
+	oe.exportObjectTypes()
+	if err := oe.exportScalarTypes(); err != nil {
+		return nil, err
+	}
+
+	for _, collection := range oe.schema.Collections {
+		resultType := schema.NewArrayType(schema.NewNamedType(collection.Type)).Encode()
+		if err := oe.exportOperation(collection.Name, collection.Description, collection.Request, collection.Arguments, resultType); err != nil {
+			return nil, fmt.Errorf("collection %s: %s", collection.Name, err)
+		}
+		if err := oe.exportCollectionExtension(collection.Request); err != nil {
+			return nil, fmt.Errorf("collection %s: %s", collection.Name, err)
+		}
+	}
+	for _, fn := range oe.schema.Functions {
+		if err := oe.exportOperation(fn.Name, fn.Description, fn.Request, fn.Arguments, fn.ResultType); err != nil {
+			return nil, fmt.Errorf("function %s: %s", fn.Name, err)
+		}
+		oe.exportOperationKindExtension(fn.Request, rest.OperationFunction)
+	}
+	for _, proc := range oe.schema.Procedures {
+		if err := oe.exportOperation(proc.Name, proc.Description, proc.Request, proc.Arguments, proc.ResultType); err != nil {
+			return nil, fmt.Errorf("procedure %s: %s", proc.Name, err)
+		}
+		oe.exportOperationKindExtension(proc.Request, rest.OperationProcedure)
+	}
+
+	return oe.document, nil
+}
+
+func (oe *OAS3Exporter) exportSettings(settings *rest.NDCRestSettings) error {
+	if settings.Version != "" {
+		oe.document.Info.Version = settings.Version
+	}
+
+	oe.document.Servers = exportServers(settings.Servers)
+	oe.document.Security = exportSecurities(settings.Security)
+
+	if err := oe.exportSecuritySchemes(settings.SecuritySchemes); err != nil {
+		return err
+	}
+	for _, server := range settings.Servers {
+		if err := oe.exportSecuritySchemes(server.SecuritySchemes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (oe *OAS3Exporter) exportSecuritySchemes(schemes map[string]rest.SecurityScheme) error {
+	for _, key := range getSortedKeys(schemes) {
+		if _, ok := oe.document.Components.SecuritySchemes.Get(key); ok {
+			continue
+		}
+		security, err := exportSecurityScheme(schemes[key])
+		if err != nil {
+			return fmt.Errorf("security scheme %s: %s", key, err)
+		}
+		oe.document.Components.SecuritySchemes.Set(key, security)
+	}
+	return nil
+}
+
+func (oe *OAS3Exporter) exportObjectTypes() {
+	for _, key := range getSortedKeys(oe.schema.ObjectTypes) {
+		// entries of typed maps are exported as additionalProperties of maps
+		if _, ok := oe.getMapEntryValueType(schema.NewNamedType(key).Encode()); ok {
+			continue
+		}
+		objectType := oe.schema.ObjectTypes[key]
+		result := &base.Schema{
+			Type:       []string{"object"},
+			Properties: orderedmap.New[string, *base.SchemaProxy](),
+		}
+		if objectType.Description != nil {
+			result.Description = *objectType.Description
+		}
+		for _, fieldName := range getSortedKeys(objectType.Fields) {
+			field := objectType.Fields[fieldName]
+			fieldSchema := oe.exportSchemaType(field.Type)
+			if field.Description != nil && !fieldSchema.IsReference() {
+				fieldSchema.Schema().Description = *field.Description
+			}
+			result.Properties.Set(fieldName, fieldSchema)
+			if !isNullableType(field.Type.Interface()) {
+				result.Required = append(result.Required, fieldName)
+			}
+		}
+		oe.document.Components.Schemas.Set(key, base.CreateSchemaProxy(result))
+	}
+}
+
+func (oe *OAS3Exporter) exportScalarTypes() error {
+	for _, key := range getSortedKeys(oe.schema.ScalarTypes) {
+		scalarSchema, err := exportScalarType(oe.schema.ScalarTypes[key])
+		if err != nil {
+			return fmt.Errorf("scalar type %s: %s", key, err)
+		}
+		if format, ok := defaultScalarFormats[rest.ScalarName(key)]; ok && len(scalarSchema.Type) > 0 {
+			scalarSchema.Format = format
+		}
+		oe.document.Components.Schemas.Set(key, base.CreateSchemaProxy(scalarSchema))
+	}
+	return nil
+}
+
+func (oe *OAS3Exporter) exportOperation(name string, description *string, request *rest.Request, arguments map[string]schema.ArgumentInfo, resultType schema.Type) error {
+	if request == nil {
+		return errors.New("request is required")
+	}
+	if request.URL == "" {
+		return errors.New("request url is required")
+	}
+
+	operation := &v3.Operation{
+		OperationId: name,
+		Security:    exportSecurities(request.Security),
+		Servers:     exportServers(request.Servers),
+	}
+	// the converter reads descriptions of operations from the summary
+	if description != nil {
+		operation.Summary = *description
+		operation.Description = *description
+	}
+
+	for _, param := range request.Parameters {
+		if param.In == rest.InBody || param.In == rest.InFormData {
+			continue
+		}
+		parameter := oe.exportParameter(param, arguments)
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+
+	requestBody, err := oe.exportRequestBody(request.RequestBody, arguments)
+	if err != nil {
+		return err
+	}
+	operation.RequestBody = requestBody
+
+	contentType := request.Response.ContentType
+	if contentType == "" {
+		contentType = rest.ContentTypeJSON
+	}
+	response := &v3.Response{
+		Description: exportResponseDesc,
+	}
+	if request.Response.Envelope {
+		// the envelope object wraps the response headers and body
+		var envelope schema.ObjectType
+		if named, ok := resultType.Interface().(*schema.NamedType); ok {
+			envelope = oe.schema.ObjectTypes[named.Name]
+		}
+		if headersField, ok := envelope.Fields[rest.ResponseEnvelopeHeadersField]; ok {
+			response.Headers = oe.exportResponseHeaders(request.Response.Headers, headersField.Type)
+		}
+		if bodyField, ok := envelope.Fields[rest.ResponseEnvelopeBodyField]; ok {
+			response.Content = orderedmap.New[string, *v3.MediaType]()
+			response.Content.Set(contentType, &v3.MediaType{
+				Schema: oe.exportSchemaType(bodyField.Type),
+			})
+		}
+	} else if len(request.Response.Headers) > 0 {
+		// the result of the body-less response is the object of response headers
+		response.Headers = oe.exportResponseHeaders(request.Response.Headers, resultType)
+	} else {
+		response.Content = orderedmap.New[string, *v3.MediaType]()
+		response.Content.Set(contentType, &v3.MediaType{
+			Schema: oe.exportSchemaType(resultType),
+		})
+	}
+	operation.Responses = &v3.Responses{
+		Codes: orderedmap.New[string, *v3.Response](),
+	}
+	if len(request.Response.StatusCodes) == 0 {
+		operation.Responses.Codes.Set(exportResponseCode, response)
+	} else {
+		oe.exportStatusCodeResponses(operation.Responses, request.Response.StatusCodes, response)
+	}
+
+	pathItem, ok := oe.document.Paths.PathItems.Get(request.URL)
+	if !ok {
+		pathItem = &v3.PathItem{}
+		oe.document.Paths.PathItems.Set(request.URL, pathItem)
+	}
+
+	method := strings.ToLower(request.Method)
+	existing := getPathItemOperation(pathItem, method)
+	if existing == nil {
+		return fmt.Errorf("unsupported request method %s", request.Method)
+	}
+	if *existing != nil {
+		return fmt.Errorf("%s %s conflicts with operation %s", method, request.URL, (*existing).OperationId)
+	}
+	*existing = operation
+	oe.Logger.Debug("exported operation", slog.String("name", name), slog.String("method", method), slog.String("path", request.URL))
+
+	return nil
+}
+
+// export documented responses by status code. Response headers are attached to the first success response
+func (oe *OAS3Exporter) exportStatusCodeResponses(responses *v3.Responses, statusCodes map[string]rest.ResponseContent, primary *v3.Response) {
+	hasPrimary := false
+	for _, code := range getSortedKeys(statusCodes) {
+		content := statusCodes[code]
+		response := &v3.Response{
+			Description: exportResponseDesc,
+		}
+		if !hasPrimary && isSuccessStatusCode(code) {
+			hasPrimary = true
+			response.Headers = primary.Headers
+		}
+		if content.Type != nil {
+			contentType := content.ContentType
+			if contentType == "" {
+				contentType = rest.ContentTypeJSON
+			}
+			response.Content = orderedmap.New[string, *v3.MediaType]()
+			response.Content.Set(contentType, &v3.MediaType{
+				Schema: oe.exportSchemaType(content.Type),
+			})
+		}
+		if code == "default" {
+			responses.Default = response
+		} else {
+			responses.Codes.Set(code, response)
+		}
+	}
+}
+
+// export response headers from fields of the result object type
+func (oe *OAS3Exporter) exportResponseHeaders(headers []rest.ResponseHeader, resultType schema.Type) *orderedmap.Map[string, *v3.Header] {
+	var object schema.ObjectType
+	if named, ok := resultType.Interface().(*schema.NamedType); ok {
+		object = oe.schema.ObjectTypes[named.Name]
+	}
+
+	results := orderedmap.New[string, *v3.Header]()
+	for _, header := range headers {
+		result := &v3.Header{}
+		if field, ok := object.Fields[header.FieldName]; ok {
+			result.Schema = oe.exportSchemaType(field.Type)
+			result.Required = !isNullableType(field.Type.Interface())
+			if field.Description != nil {
+				result.Description = *field.Description
+			}
+		} else if header.Schema != nil {
+			result.Schema = oe.exportTypeSchema(header.Schema)
+			result.Required = !header.Schema.Nullable
+		}
+		results.Set(header.Name, result)
+	}
+	return results
+}
+
+// get the operation slot of the path item by the lowercase HTTP method. Returns nil if the method is unsupported
+func getPathItemOperation(pathItem *v3.PathItem, method string) **v3.Operation {
+	switch method {
+	case "get":
+		return &pathItem.Get
+	case "post":
+		return &pathItem.Post
+	case "put":
+		return &pathItem.Put
+	case "patch":
+		return &pathItem.Patch
+	case "delete":
+		return &pathItem.Delete
+	case "head":
+		return &pathItem.Head
+	case "options":
+		return &pathItem.Options
+	case "trace":
+		return &pathItem.Trace
+	default:
+		return nil
+	}
+}
+
+// add the x-ndc-operation extension to the exported operation if the operation kind isn't the default kind of the HTTP method,
+// so the operation can be converted back to the same kind
+func (oe *OAS3Exporter) exportOperationKindExtension(request *rest.Request, kind rest.OperationKind) {
+	method := strings.ToLower(request.Method)
+	if getDefaultOperationKind(method) == kind {
+		return
+	}
+	pathItem, ok := oe.document.Paths.PathItems.Get(request.URL)
+	if !ok {
+		return
+	}
+	operation := getPathItemOperation(pathItem, method)
+	if operation == nil || *operation == nil {
+		return
+	}
+	if (*operation).Extensions == nil {
+		(*operation).Extensions = orderedmap.New[string, *yaml.Node]()
+	}
+	(*operation).Extensions.Set(operationKindExtension, &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: string(kind),
+	})
+}
+
+// add the x-ndc-collection extension to the exported operation of the collection, so the collection can be converted back.
+// The response is exported as the item array, so the items and next cursor fields are omitted
+func (oe *OAS3Exporter) exportCollectionExtension(request *rest.Request) error {
+	pathItem, ok := oe.document.Paths.PathItems.Get(request.URL)
+	if !ok || pathItem.Get == nil {
+		return nil
+	}
+
+	value := collectionExtensionValue{}
+	if request.Pagination != nil {
+		pagination := *request.Pagination
+		pagination.ItemsField = ""
+		pagination.NextCursorField = ""
+		value.Pagination = &pagination
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	if pathItem.Get.Extensions == nil {
+		pathItem.Get.Extensions = orderedmap.New[string, *yaml.Node]()
+	}
+	pathItem.Get.Extensions.Set(collectionExtension, node)
+	return nil
+}
+
+func (oe *OAS3Exporter) exportParameter(param rest.RequestParameter, arguments map[string]schema.ArgumentInfo) *v3.Parameter {
+	argumentName := param.ArgumentName
+	if argumentName == "" {
+		argumentName = param.Name
+	}
+
+	result := &v3.Parameter{
+		Name:          param.Name,
+		In:            string(param.In),
+		Style:         string(param.Style),
+		Explode:       param.Explode,
+		AllowReserved: param.AllowReserved,
+	}
+
+	argument, hasArgument := arguments[argumentName]
+	if hasArgument && argument.Description != nil {
+		result.Description = *argument.Description
+	}
+	required := param.In == rest.InPath || (hasArgument && !isNullableType(argument.Type.Interface()))
+	result.Required = &required
+
+	// the argument type is preferred if the type schema doesn't have the element type of the array
+	if param.Schema != nil && (param.Schema.Type != "array" || param.Schema.Items != nil || !hasArgument) {
+		result.Schema = oe.exportTypeSchema(param.Schema)
+	} else if hasArgument {
+		result.Schema = oe.exportSchemaType(argument.Type)
+	}
+
+	return result
+}
+
+func (oe *OAS3Exporter) exportRequestBody(requestBody *rest.RequestBody, arguments map[string]schema.ArgumentInfo) (*v3.RequestBody, error) {
+	bodyArgument, hasBody := arguments["body"]
+	if requestBody == nil && !hasBody {
+		return nil, nil
+	}
+
+	contentType := rest.ContentTypeJSON
+	mediaType := &v3.MediaType{}
+	if requestBody != nil {
+		if requestBody.ContentType != "" {
+			contentType = requestBody.ContentType
+		}
+		if requestBody.Schema != nil {
+			mediaType.Schema = oe.exportTypeSchema(requestBody.Schema)
+		}
+		if len(requestBody.Encoding) > 0 {
+			mediaType.Encoding = orderedmap.New[string, *v3.Encoding]()
+			for _, key := range getSortedKeys(requestBody.Encoding) {
+				mediaType.Encoding.Set(key, oe.exportEncoding(requestBody.Encoding[key]))
+			}
+		}
+	}
+
+	required := false
+	result := &v3.RequestBody{
+		Content:  orderedmap.New[string, *v3.MediaType](),
+		Required: &required,
+	}
+	if hasBody {
+		required = !isNullableType(bodyArgument.Type.Interface())
+		mediaType.Schema = oe.exportSchemaType(bodyArgument.Type)
+		if bodyArgument.Description != nil {
+			result.Description = *bodyArgument.Description
+		}
+	}
+	if mediaType.Schema == nil {
+		return nil, errors.New("schema of the request body is required")
+	}
+	result.Content.Set(contentType, mediaType)
+
+	return result, nil
+}
+
+func (oe *OAS3Exporter) exportEncoding(encoding rest.EncodingObject) *v3.Encoding {
+	result := &v3.Encoding{
+		ContentType:   strings.Join(encoding.ContentType, ", "),
+		Style:         string(encoding.Style),
+		Explode:       encoding.Explode,
+		AllowReserved: encoding.AllowReserved,
+	}
+	if len(encoding.Headers) > 0 {
+		result.Headers = orderedmap.New[string, *v3.Header]()
+		for _, key := range getSortedKeys(encoding.Headers) {
+			header := encoding.Headers[key]
+			h := &v3.Header{
+				Style:         string(header.Style),
+				AllowReserved: header.AllowReserved,
+			}
+			if header.Explode != nil {
+				h.Explode = *header.Explode
+			}
+			if header.Schema != nil {
+				h.Schema = oe.exportTypeSchema(header.Schema)
+			}
+			result.Headers.Set(key, h)
+		}
+	}
+	return result
+}
+
+func exportServers(servers []rest.ServerConfig) []*v3.Server {
+	var results []*v3.Server
+	for _, server := range servers {
+		result := &v3.Server{}
+		switch {
+		case !server.URL.EnvTemplate.IsEmpty() && server.URL.EnvTemplate.DefaultValue != nil:
+			result.URL = *server.URL.EnvTemplate.DefaultValue
+		case !server.URL.EnvTemplate.IsEmpty():
+			// use a server variable for the environment variable without default value
+			result.URL = fmt.Sprintf("{%s}", server.URL.EnvTemplate.Name)
+			result.Variables = orderedmap.New[string, *v3.ServerVariable]()
+			result.Variables.Set(server.URL.EnvTemplate.Name, &v3.ServerVariable{
+				Default:     "",
+				Description: fmt.Sprintf("The value of the %s environment variable", server.URL.EnvTemplate.Name),
+			})
+		default:
+			result.URL = server.URL.String()
+		}
+		if result.URL == "" {
+			continue
+		}
+		if server.ID != "" {
+			result.Extensions = orderedmap.New[string, *yaml.Node]()
+			result.Extensions.Set("x-server-id", &yaml.Node{
+				Kind:  yaml.ScalarNode,
+				Tag:   "!!str",
+				Value: server.ID,
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func exportSecurities(securities rest.AuthSecurities) []*base.SecurityRequirement {
+	if securities == nil {
+		return nil
+	}
+	results := make([]*base.SecurityRequirement, 0, len(securities))
+	for _, security := range securities {
+		requirement := &base.SecurityRequirement{
+			Requirements: orderedmap.New[string, []string](),
+		}
+		if len(security) == 0 {
+			requirement.ContainsEmptyRequirement = true
+		}
+		for _, key := range getSortedKeys(security) {
+			scopes := security[key]
+			if scopes == nil {
+				scopes = []string{}
+			}
+			requirement.Requirements.Set(key, scopes)
+		}
+		results = append(results, requirement)
+	}
+	return results
+}
+
+func exportSecurityScheme(security rest.SecurityScheme) (*v3.SecurityScheme, error) {
+	result := &v3.SecurityScheme{
+		Type: string(security.Type),
+	}
+	switch security.Type {
+	case rest.APIKeyScheme:
+		if security.APIKeyAuthConfig == nil {
+			return nil, errors.New("apiKey configuration is required")
+		}
+		result.In = string(security.In)
+		result.Name = security.Name
+	case rest.HTTPAuthScheme:
+		if security.HTTPAuthConfig == nil {
+			return nil, errors.New("http configuration is required")
+		}
+		result.Scheme = security.Scheme
+	case rest.OAuth2Scheme:
+		if security.OAuth2Config == nil {
+			return nil, errors.New("oauth2 configuration is required")
+		}
+		result.Flows = &v3.OAuthFlows{}
+		for flowType, flow := range security.Flows {
+			oauthFlow := exportOAuthFlow(flow)
+			switch flowType {
+			case rest.ImplicitFlow:
+				result.Flows.Implicit = oauthFlow
+			case rest.PasswordFlow:
+				result.Flows.Password = oauthFlow
+			case rest.ClientCredentialsFlow:
+				result.Flows.ClientCredentials = oauthFlow
+			case rest.AuthorizationCodeFlow:
+				result.Flows.AuthorizationCode = oauthFlow
+			}
+		}
+	case rest.OpenIDConnectScheme:
+		if security.OpenIDConfig == nil {
+			return nil, errors.New("openIdConnect configuration is required")
+		}
+		result.OpenIdConnectUrl = security.OpenIDConnectURL
+	default:
+		return nil, fmt.Errorf("invalid security scheme: %s", security.Type)
+	}
+	return result, nil
+}
+
+func exportOAuthFlow(flow rest.OAuthFlow) *v3.OAuthFlow {
+	result := &v3.OAuthFlow{
+		AuthorizationUrl: flow.AuthorizationURL,
+		TokenUrl:         flow.TokenURL,
+		RefreshUrl:       flow.RefreshURL,
+		Scopes:           orderedmap.New[string, string](),
+	}
+	for _, key := range getSortedKeys(flow.Scopes) {
+		result.Scopes.Set(key, flow.Scopes[key])
+	}
+	return result
+}
+
+// exportScalarType converts the type representation of a scalar type to the OpenAPI schema
+func exportScalarType(scalarType schema.ScalarType) (*base.Schema, error) {
+	if scalarType.Representation == nil {
+		return &base.Schema{}, nil
+	}
+	representation, err := scalarType.Representation.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	switch representation {
+	case schema.TypeRepresentationTypeBoolean:
+		return &base.Schema{Type: []string{"boolean"}}, nil
+	case schema.TypeRepresentationTypeString:
+		return &base.Schema{Type: []string{"string"}}, nil
+	case schema.TypeRepresentationTypeInt8:
+		return &base.Schema{Type: []string{"integer"}, Format: "int8"}, nil
+	case schema.TypeRepresentationTypeInt16:
+		return &base.Schema{Type: []string{"integer"}, Format: "int16"}, nil
+	case schema.TypeRepresentationTypeInt32:
+		return &base.Schema{Type: []string{"integer"}, Format: "int32"}, nil
+	case schema.TypeRepresentationTypeInt64:
+		return &base.Schema{Type: []string{"integer"}, Format: "int64"}, nil
+	case schema.TypeRepresentationTypeInteger, schema.TypeRepresentationTypeBigInteger:
+		return &base.Schema{Type: []string{"integer"}}, nil
+	case schema.TypeRepresentationTypeFloat32:
+		return &base.Schema{Type: []string{"number"}, Format: "float"}, nil
+	case schema.TypeRepresentationTypeFloat64:
+		return &base.Schema{Type: []string{"number"}, Format: "double"}, nil
+	case schema.TypeRepresentationTypeNumber, schema.TypeRepresentationTypeBigDecimal:
+		return &base.Schema{Type: []string{"number"}}, nil
+	case schema.TypeRepresentationTypeUUID:
+		return &base.Schema{Type: []string{"string"}, Format: "uuid"}, nil
+	case schema.TypeRepresentationTypeDate:
+		return &base.Schema{Type: []string{"string"}, Format: "date"}, nil
+	case schema.TypeRepresentationTypeTimestamp, schema.TypeRepresentationTypeTimestampTZ:
+		return &base.Schema{Type: []string{"string"}, Format: "date-time"}, nil
+	case schema.TypeRepresentationTypeBytes:
+		return &base.Schema{Type: []string{"string"}, Format: "byte"}, nil
+	case schema.TypeRepresentationTypeGeography, schema.TypeRepresentationTypeGeometry:
+		return &base.Schema{Type: []string{"object"}}, nil
+	case schema.TypeRepresentationTypeEnum:
+		enumRepresentation, err := scalarType.Representation.AsEnum()
+		if err != nil {
+			return nil, err
+		}
+		return exportEnumSchema(enumRepresentation.OneOf), nil
+	default:
+		// JSON scalar accepts any value
+		return &base.Schema{}, nil
+	}
+}
+
+// exportSchemaType converts the NDC type to the OpenAPI schema.
+// Named types are referenced to component schemas. Arrays of map entries are exported as maps
+func (oe *OAS3Exporter) exportSchemaType(schemaType schema.Type) *base.SchemaProxy {
+	if schemaType == nil {
+		return base.CreateSchemaProxy(&base.Schema{})
+	}
+	switch ty := schemaType.Interface().(type) {
+	case *schema.NamedType:
+		return base.CreateSchemaProxyRef(componentSchemaRefPrefix + ty.Name)
+	case *schema.NullableType:
+		underlyingSchema := oe.exportSchemaType(ty.UnderlyingType)
+		// $ref siblings are ignored in OpenAPI 3.0, so the reference must be wrapped
+		if underlyingSchema.IsReference() {
+			return base.CreateSchemaProxy(&base.Schema{
+				AllOf:    []*base.SchemaProxy{underlyingSchema},
+				Nullable: toPtr(true),
+			})
+		}
+		underlyingSchema.Schema().Nullable = toPtr(true)
+		return underlyingSchema
+	case *schema.ArrayType:
+		if valueType, ok := oe.getMapEntryValueType(ty.ElementType); ok {
+			return base.CreateSchemaProxy(&base.Schema{
+				Type: []string{"object"},
+				AdditionalProperties: &base.DynamicValue[*base.SchemaProxy, bool]{
+					A: oe.exportSchemaType(valueType),
+				},
+			})
+		}
+		return base.CreateSchemaProxy(&base.Schema{
+			Type: []string{"array"},
+			Items: &base.DynamicValue[*base.SchemaProxy, bool]{
+				A: oe.exportSchemaType(ty.ElementType),
+			},
+		})
+	default:
+		return base.CreateSchemaProxy(&base.Schema{})
+	}
+}
+
+// exportTypeSchema converts the REST type schema to the OpenAPI schema.
+// The type of the schema can be either an OpenAPI type or a NDC type name
+func (oe *OAS3Exporter) exportTypeSchema(typeSchema *rest.TypeSchema) *base.SchemaProxy {
+	result := &base.Schema{
+		Format:        typeSchema.Format,
+		Pattern:       typeSchema.Pattern,
+		Maximum:       typeSchema.Maximum,
+		Minimum:       typeSchema.Minimum,
+		MultipleOf:    typeSchema.MultipleOf,
+		MaxLength:     typeSchema.MaxLength,
+		MinLength:     typeSchema.MinLength,
+		MaxItems:      typeSchema.MaxItems,
+		MinItems:      typeSchema.MinItems,
+		MaxProperties: typeSchema.MaxProperties,
+		MinProperties: typeSchema.MinProperties,
+		Description:   typeSchema.Description,
+		Enum:          exportEnumNodes(typeSchema.Enum),
+		Default:       exportNodeValue(typeSchema.Default),
+		Example:       exportNodeValue(typeSchema.Example),
+	}
+	// the exported document is OpenAPI 3.0 that only supports boolean exclusive bounds
+	if typeSchema.ExclusiveMaximum != nil {
+		result.Maximum = typeSchema.ExclusiveMaximum
+		result.ExclusiveMaximum = &base.DynamicValue[bool, float64]{A: true}
+	}
+	if typeSchema.ExclusiveMinimum != nil {
+		result.Minimum = typeSchema.ExclusiveMinimum
+		result.ExclusiveMinimum = &base.DynamicValue[bool, float64]{A: true}
+	}
+	if len(result.Enum) == 0 && typeSchema.Const != nil {
+		result.Enum = exportEnumNodes([]any{typeSchema.Const})
+	}
+	if typeSchema.UniqueItems {
+		result.UniqueItems = toPtr(true)
+	}
+	if typeSchema.Nullable {
+		result.Nullable = toPtr(true)
+	}
+	if typeSchema.ReadOnly {
+		result.ReadOnly = toPtr(true)
+	}
+	if typeSchema.WriteOnly {
+		result.WriteOnly = toPtr(true)
+	}
+	if typeSchema.MapEntries && typeSchema.Items != nil {
+		// typed maps are converted to arrays of key-value entries, so they are exported back to maps
+		result.Type = []string{"object"}
+		valueSchema := typeSchema.Items.Properties["value"]
+		result.AdditionalProperties = &base.DynamicValue[*base.SchemaProxy, bool]{
+			A: oe.exportTypeSchema(&valueSchema),
+		}
+		result.MinProperties, result.MaxProperties = result.MinItems, result.MaxItems
+		result.MinItems, result.MaxItems = nil, nil
+		return base.CreateSchemaProxy(result)
+	}
+	if typeSchema.Items != nil {
+		result.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
+			A: oe.exportTypeSchema(typeSchema.Items),
+		}
+	}
+	if len(typeSchema.Properties) > 0 {
+		result.Properties = orderedmap.New[string, *base.SchemaProxy]()
+		for _, key := range getSortedKeys(typeSchema.Properties) {
+			prop := typeSchema.Properties[key]
+			result.Properties.Set(key, oe.exportTypeSchema(&prop))
+		}
+	}
+
+	switch {
+	case typeSchema.Type == "":
+	case typeSchema.Type == "file":
+		result.Type = []string{"string"}
+		result.Format = "binary"
+	case typeSchema.Type == "long":
+		result.Type = []string{"integer"}
+		result.Format = "int64"
+	case isOASType(typeSchema.Type):
+		result.Type = []string{typeSchema.Type}
+		if typeSchema.Type == "array" && result.Items == nil {
+			result.Items = &base.DynamicValue[*base.SchemaProxy, bool]{
+				A: base.CreateSchemaProxy(&base.Schema{}),
+			}
+		}
+	case oe.hasNamedType(typeSchema.Type):
+		// the format is exported with the component schema of the named type
+		result.Format = ""
+		ref := base.CreateSchemaProxyRef(componentSchemaRefPrefix + typeSchema.Type)
+		// $ref siblings are ignored in OpenAPI 3.0, so the reference is wrapped if there are other keywords
+		if isEmptyExportSchema(result) {
+			return ref
+		}
+		result.AllOf = []*base.SchemaProxy{ref}
+	}
+
+	return base.CreateSchemaProxy(result)
+}
+
+// get the value type if the element type is the key-value entry object of a typed map, e.g. PricesEntry { key: String, value: Price }
+func (oe *OAS3Exporter) getMapEntryValueType(elementType schema.Type) (schema.Type, bool) {
+	named, ok := elementType.Interface().(*schema.NamedType)
+	if !ok || !strings.HasSuffix(named.Name, "Entry") {
+		return nil, false
+	}
+	object, ok := oe.schema.ObjectTypes[named.Name]
+	if !ok || len(object.Fields) != 2 {
+		return nil, false
+	}
+	key, hasKey := object.Fields["key"]
+	value, hasValue := object.Fields["value"]
+	if !hasKey || !hasValue || getNamedType(key.Type.Interface(), false, "") != string(rest.ScalarString) {
+		return nil, false
+	}
+	return value.Type, true
+}
+
+func (oe *OAS3Exporter) hasNamedType(name string) bool {
+	if _, ok := oe.schema.ObjectTypes[name]; ok {
+		return true
+	}
+	_, ok := oe.schema.ScalarTypes[name]
+	return ok
+}
+
+func isEmptyExportSchema(input *base.Schema) bool {
+	return input.Format == "" && input.Pattern == "" && input.Maximum == nil && input.Minimum == nil && input.MultipleOf == nil &&
+		input.MaxLength == nil && input.MinLength == nil && input.MaxItems == nil && input.MinItems == nil && input.UniqueItems == nil &&
+		input.MaxProperties == nil && input.MinProperties == nil && input.Description == "" && len(input.Enum) == 0 &&
+		input.Default == nil && input.Example == nil &&
+		input.Nullable == nil && input.ReadOnly == nil && input.WriteOnly == nil && input.Items == nil && input.Properties == nil
+}
+
+func exportNodeValue(value any) *yaml.Node {
+	if value == nil {
+		return nil
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil
+	}
+	return node
+}
+
+// exportEnumSchema converts values of the enum scalar to the OpenAPI schema.
+// Enum scalars only keep string values, so the type is inferred from values. Numeric values are exported as integer or number
+func exportEnumSchema(values []string) *base.Schema {
+	typeName, tag := "integer", "!!int"
+	for _, value := range values {
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			typeName, tag = "number", "!!float"
+			continue
+		}
+		typeName = "string"
+		break
+	}
+	if len(values) == 0 || typeName == "string" {
+		return &base.Schema{
+			Type: []string{"string"},
+			Enum: exportEnumNodes(values),
+		}
+	}
+	nodes := make([]*yaml.Node, len(values))
+	for i, value := range values {
+		nodes[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	}
+	return &base.Schema{
+		Type: []string{typeName},
+		Enum: nodes,
+	}
+}
+
+func exportEnumNodes[T any](values []T) []*yaml.Node {
+	if len(values) == 0 {
+		return nil
+	}
+	results := make([]*yaml.Node, 0, len(values))
+	for _, value := range values {
+		node := &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			continue
+		}
+		results = append(results, node)
+	}
+	return results
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/hasura/ndc-rest-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
 )
 
 type oas3OperationBuilder struct {
@@ -19,6 +21,8 @@ type oas3OperationBuilder struct {
 	method        string
 	Arguments     map[string]schema.ArgumentInfo
 	RequestParams []rest.RequestParameter
+	// the reason if the operation can't be converted
+	skipReason string
 }
 
 func newOAS3OperationBuilder(builder *OAS3Builder, pathKey string, method string) *oas3OperationBuilder {
@@ -32,9 +36,12 @@ func newOAS3OperationBuilder(builder *OAS3Builder, pathKey string, method string
 
 // BuildFunction build a REST NDC function information from OpenAPI v3 operation
 func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.RESTFunctionInfo, error) {
+	if itemGet == nil {
+		return nil, nil
+	}
 	funcName := itemGet.OperationId
 	if funcName == "" {
-		funcName = buildPathMethodName(oc.pathKey, "get", oc.builder.ConvertOptions)
+		funcName = buildPathMethodName(oc.pathKey, oc.method, oc.builder.ConvertOptions)
 	}
 	if oc.builder.Prefix != "" {
 		funcName = utils.StringSliceToCamelCase([]string{oc.builder.Prefix, funcName})
@@ -50,6 +57,7 @@ func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.REST
 		return nil, fmt.Errorf("%s: %s", oc.pathKey, err)
 	}
 	if resultType == nil {
+		oc.builder.report.addSkippedOperation(oc.pathKey, oc.method, itemGet.OperationId, oc.skipReason)
 		return nil, nil
 	}
 
@@ -58,14 +66,20 @@ func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.REST
 		return nil, fmt.Errorf("%s: %s", funcName, err)
 	}
 
+	reqBody, err := oc.convertBodyArgument(itemGet.RequestBody, funcName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", oc.pathKey, err)
+	}
+
 	function := rest.RESTFunctionInfo{
 		Request: &rest.Request{
-			URL:        oc.pathKey,
-			Method:     "get",
-			Parameters: sortRequestParameters(oc.RequestParams),
-			Security:   convertSecurities(itemGet.Security),
-			Servers:    oc.builder.convertServers(itemGet.Servers),
-			Response:   *schemaResponse,
+			URL:         oc.pathKey,
+			Method:      oc.method,
+			Parameters:  sortRequestParameters(oc.RequestParams),
+			Security:    convertSecurities(itemGet.Security),
+			Servers:     oc.builder.convertServers(itemGet.Servers),
+			RequestBody: reqBody,
+			Response:    *schemaResponse,
 		},
 		FunctionInfo: schema.FunctionInfo{
 			Name:       funcName,
@@ -105,6 +119,7 @@ func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.R
 	}
 
 	if resultType == nil {
+		oc.builder.report.addSkippedOperation(oc.pathKey, oc.method, operation.OperationId, oc.skipReason)
 		return nil, nil
 	}
 
@@ -113,22 +128,10 @@ func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.R
 		return nil, fmt.Errorf("%s: %s", oc.pathKey, err)
 	}
 
-	reqBody, schemaType, err := oc.convertRequestBody(operation.RequestBody, oc.pathKey, []string{procName, "Body"})
+	reqBody, err := oc.convertBodyArgument(operation.RequestBody, procName)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s", oc.pathKey, err)
 	}
-	if reqBody != nil {
-		description := fmt.Sprintf("Request body of %s %s", strings.ToUpper(oc.method), oc.pathKey)
-		// renaming query parameter name `body` if exist to avoid conflicts
-		if paramData, ok := oc.Arguments["body"]; ok {
-			oc.Arguments["paramBody"] = paramData
-		}
-
-		oc.Arguments["body"] = schema.ArgumentInfo{
-			Description: &description,
-			Type:        schemaType.Encode(),
-		}
-	}
 
 	procedure := rest.RESTProcedureInfo{
 		Request: &rest.Request{
@@ -154,13 +157,34 @@ func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.R
 	return &procedure, nil
 }
 
+// convert the request body to the body argument of the operation
+func (oc *oas3OperationBuilder) convertBodyArgument(requestBody *v3.RequestBody, operationName string) (*rest.RequestBody, error) {
+	reqBody, schemaType, err := oc.convertRequestBody(requestBody, oc.pathKey, []string{operationName, "Body"})
+	if err != nil || reqBody == nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Request body of %s %s", strings.ToUpper(oc.method), oc.pathKey)
+	// renaming query parameter name `body` if exist to avoid conflicts
+	if paramData, ok := oc.Arguments["body"]; ok {
+		oc.Arguments["paramBody"] = paramData
+		oc.builder.report.addNameCollision("argument", "body", "paramBody", operationName)
+	}
+
+	oc.Arguments["body"] = schema.ArgumentInfo{
+		Description: &description,
+		Type:        schemaType.Encode(),
+	}
+	return reqBody, nil
+}
+
 func (oc *oas3OperationBuilder) convertParameters(params []*v3.Parameter, apiPath string, fieldPaths []string) error {
 
 	if len(params) == 0 {
 		return nil
 	}
 
-	for _, param := range params {
+	for i, param := range params {
 		if param == nil {
 			continue
 		}
@@ -174,7 +198,7 @@ func (oc *oas3OperationBuilder) convertParameters(params []*v3.Parameter, apiPat
 		}
 		paramPaths := append(fieldPaths, paramName)
 		schemaType, apiSchema, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, rest.ParameterLocation(param.In), true).
-			getSchemaTypeFromProxy(param.Schema, !paramRequired, paramPaths)
+			getSchemaTypeFromProxy(param.Schema, !paramRequired, paramPaths, oc.getJSONPointer("parameters", fmt.Sprint(i), "schema"))
 		if err != nil {
 			return err
 		}
@@ -238,7 +262,7 @@ func (oc *oas3OperationBuilder) convertRequestBody(reqBody *v3.RequestBody, apiP
 		location = rest.InQuery
 	}
 	schemaType, typeSchema, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, location, true).
-		getSchemaTypeFromProxy(content.Schema, !bodyRequired, fieldPaths)
+		getSchemaTypeFromProxy(content.Schema, !bodyRequired, fieldPaths, oc.getJSONPointer("requestBody", "content", contentType, "schema"))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -285,7 +309,7 @@ func (oc *oas3OperationBuilder) convertRequestBody(reqBody *v3.RequestBody, apiP
 					}
 
 					ndcType, typeSchema, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, rest.InHeader, true).
-						getSchemaTypeFromProxy(header.Schema, header.AllowEmptyValue, append(fieldPaths, key))
+						getSchemaTypeFromProxy(header.Schema, header.AllowEmptyValue, append(fieldPaths, key), oc.getJSONPointer("requestBody", "content", contentType, "encoding", iter.Key(), "headers", encodingHeader.Key(), "schema"))
 					if err != nil {
 						return nil, nil, err
 					}
@@ -332,10 +356,12 @@ func (oc *oas3OperationBuilder) convertRequestBody(reqBody *v3.RequestBody, apiP
 
 func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath string, fieldPaths []string) (schema.TypeEncoder, *rest.Response, error) {
 	if responses == nil || responses.Codes == nil || responses.Codes.IsZero() {
+		oc.skipReason = "no response status code"
 		return nil, nil, nil
 	}
 
 	var resp *v3.Response
+	var statusCode string
 	if responses.Codes != nil && !responses.Codes.IsZero() {
 		for r := responses.Codes.First(); r != nil; r = r.Next() {
 			if r.Key() == "" {
@@ -347,21 +373,155 @@ func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath
 			}
 
 			if isUnsupportedResponseCodes(code) {
+				oc.skipReason = fmt.Sprintf("unsupported response status code %d", code)
 				return nil, nil, nil
 			} else if code >= 200 && code < 300 {
 				resp = r.Value()
+				statusCode = r.Key()
 				break
 			}
 		}
 	}
 
-	// return nullable boolean type if the response content is null
+	// responses of extra methods such as HEAD usually don't have the body.
+	// The result is the object of response headers if they are documented
+	if resp != nil && slices.Contains(extraMethods, oc.method) && (resp.Content == nil || oc.method == "head") {
+		headersType, headers, err := oc.convertResponseHeaders(resp.Headers, fieldPaths, oc.getJSONPointer("responses", statusCode, "headers"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if headersType != nil {
+			return headersType, &rest.Response{
+				ContentType: rest.ContentTypeJSON,
+				Headers:     headers,
+			}, nil
+		}
+		resp = nil
+	}
+
+	resultType, typeSchema, schemaResponse, err := oc.convertResponseBody(resp, statusCode, apiPath, fieldPaths)
+	if err != nil || resultType == nil || resp == nil {
+		return resultType, schemaResponse, err
+	}
+	if oc.builder.StatusCodeResponses {
+		resultType, err = oc.convertStatusCodeResponses(responses, resultType, typeSchema, schemaResponse, statusCode, apiPath, fieldPaths)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if !oc.builder.ResponseHeaders {
+		return resultType, schemaResponse, nil
+	}
+
+	// wrap the result in the envelope object with response headers
+	headersType, headers, err := oc.convertResponseHeaders(resp.Headers, append(slices.Clone(fieldPaths), "Headers"), oc.getJSONPointer("responses", statusCode, "headers"))
+	if err != nil || headersType == nil {
+		return resultType, schemaResponse, err
+	}
+	schemaResponse.Headers = headers
+	schemaResponse.Envelope = true
+	return buildResponseEnvelope(oc.builder.schema, oc.builder.typeUsageCounter, resultType, headersType, fieldPaths), schemaResponse, nil
+}
+
+// convert responses of all documented status codes. Success responses with different types are merged into a union object type
+// and error payloads are converted to object types
+func (oc *oas3OperationBuilder) convertStatusCodeResponses(responses *v3.Responses, resultType schema.TypeEncoder, typeSchema *rest.TypeSchema, schemaResponse *rest.Response, statusCode string, apiPath string, fieldPaths []string) (schema.TypeEncoder, error) {
+	schemaResponse.StatusCodes = map[string]rest.ResponseContent{}
+	primaryResponse, _ := responses.Codes.Get(statusCode)
+	var members []responseUnionMember
+	hasBodyless := primaryResponse.Content == nil
+	if hasBodyless {
+		schemaResponse.StatusCodes[statusCode] = rest.ResponseContent{}
+	} else {
+		schemaResponse.StatusCodes[statusCode] = rest.ResponseContent{
+			ContentType: schemaResponse.ContentType,
+			Schema:      typeSchema,
+			Type:        resultType.Encode(),
+		}
+		members = append(members, responseUnionMember{StatusCode: statusCode, Type: resultType})
+	}
+
+	errorPaths := append(slices.Clone(fieldPaths[:len(fieldPaths)-1]), "Error")
+	for r := responses.Codes.First(); r != nil; r = r.Next() {
+		code := r.Key()
+		resp := r.Value()
+		if code == statusCode || resp == nil || (!isSuccessStatusCode(code) && !isErrorStatusCode(code)) {
+			continue
+		}
+		if resp.Content == nil {
+			schemaResponse.StatusCodes[code] = rest.ResponseContent{}
+			hasBodyless = hasBodyless || isSuccessStatusCode(code)
+			continue
+		}
+
+		bodyPaths := append(slices.Clone(fieldPaths), code)
+		if isErrorStatusCode(code) {
+			bodyPaths = append(slices.Clone(errorPaths), code)
+		}
+		// other responses are optional and don't skip the operation
+		bodyType, bodySchema, bodyResponse, _, err := oc.convertResponseContent(resp, code, apiPath, bodyPaths)
+		if err != nil {
+			return nil, err
+		}
+		// ignore the response if the content type isn't allowed
+		if bodyType == nil {
+			continue
+		}
+		schemaResponse.StatusCodes[code] = rest.ResponseContent{
+			ContentType: bodyResponse.ContentType,
+			Schema:      bodySchema,
+			Type:        bodyType.Encode(),
+		}
+		if isSuccessStatusCode(code) {
+			members = append(members, responseUnionMember{StatusCode: code, Type: bodyType})
+		}
+	}
+	if responses.Default != nil {
+		if responses.Default.Content == nil {
+			schemaResponse.StatusCodes["default"] = rest.ResponseContent{}
+		} else {
+			bodyType, bodySchema, bodyResponse, _, err := oc.convertResponseContent(responses.Default, "default", apiPath, append(slices.Clone(errorPaths), "default"))
+			if err != nil {
+				return nil, err
+			}
+			if bodyType != nil {
+				schemaResponse.StatusCodes["default"] = rest.ResponseContent{
+					ContentType: bodyResponse.ContentType,
+					Schema:      bodySchema,
+					Type:        bodyType.Encode(),
+				}
+			}
+		}
+	}
+
+	if unionType := buildResponseUnion(oc.builder.schema, oc.builder.typeUsageCounter, members, hasBodyless, schemaResponse.StatusCodes, fieldPaths); unionType != nil {
+		return unionType, nil
+	}
+	return resultType, nil
+}
+
+// convert the response body to the result type. Returns the nullable boolean type if the response content is null.
+// The operation is skipped if the body can't be converted
+func (oc *oas3OperationBuilder) convertResponseBody(resp *v3.Response, statusCode string, apiPath string, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, *rest.Response, error) {
+	schemaType, typeSchema, schemaResponse, skipReason, err := oc.convertResponseContent(resp, statusCode, apiPath, fieldPaths)
+	if skipReason != "" {
+		oc.skipReason = skipReason
+	}
+	return schemaType, typeSchema, schemaResponse, err
+}
+
+// convert the response content to the result type. Returns the reason if the content can't be converted,
+// so the caller decides whether the operation is skipped
+func (oc *oas3OperationBuilder) convertResponseContent(resp *v3.Response, statusCode string, apiPath string, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, *rest.Response, string, error) {
 	if resp == nil || resp.Content == nil {
 		scalarName := string(rest.ScalarBoolean)
+		if _, ok := oc.builder.schema.ScalarTypes[scalarName]; !ok {
+			oc.builder.schema.ScalarTypes[scalarName] = *defaultScalarTypes[rest.ScalarBoolean]
+		}
 		oc.builder.typeUsageCounter.Add(scalarName, 1)
-		return schema.NewNullableNamedType(scalarName), &rest.Response{
+		return schema.NewNullableNamedType(scalarName), nil, &rest.Response{
 			ContentType: rest.ContentTypeJSON,
-		}, nil
+		}, "", nil
 	}
 
 	contentType := rest.ContentTypeJSON
@@ -384,13 +544,16 @@ func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath
 	}
 
 	if !present {
-		return nil, nil, nil
+		return nil, nil, nil, fmt.Sprintf("no response content type in allowed content types %v", oc.builder.AllowedContentTypes), nil
 	}
 
-	schemaType, _, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, rest.InBody, false).
-		getSchemaTypeFromProxy(bodyContent.Schema, false, fieldPaths)
+	schemaType, typeSchema, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, rest.InBody, false).
+		getSchemaTypeFromProxy(bodyContent.Schema, false, fieldPaths, oc.getJSONPointer("responses", statusCode, "content", contentType, "schema"))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, "", err
+	}
+	if schemaType == nil {
+		return nil, nil, nil, "the response schema has no type", nil
 	}
 	oc.builder.typeUsageCounter.Add(getNamedType(schemaType, true, ""), 1)
 
@@ -401,8 +564,83 @@ func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath
 	case rest.ContentTypeNdJSON:
 		// Newline Delimited JSON (ndjson) format represents a stream of structured objects
 		// so the response would be wrapped with an array
-		return schema.NewArrayType(schemaType), schemaResponse, nil
+		return schema.NewArrayType(schemaType), &rest.TypeSchema{Type: "array", Items: typeSchema}, schemaResponse, "", nil
 	default:
-		return schemaType, schemaResponse, nil
+		return schemaType, typeSchema, schemaResponse, "", nil
+	}
+}
+
+// convert response headers to fields of an object type. Returns nil if the response doesn't have any header
+func (oc *oas3OperationBuilder) convertResponseHeaders(headers *orderedmap.Map[string, *v3.Header], fieldPaths []string, pointer string) (schema.TypeEncoder, []rest.ResponseHeader, error) {
+	if headers == nil || headers.IsZero() {
+		return nil, nil, nil
+	}
+
+	object := schema.ObjectType{
+		Fields: make(schema.ObjectTypeFields),
+	}
+	var results []rest.ResponseHeader
+	fieldLocations := make(map[string]SourceLocation)
+	for pair := headers.First(); pair != nil; pair = pair.Next() {
+		headerName := pair.Key()
+		header := pair.Value()
+		if header == nil {
+			continue
+		}
+		fieldName := utils.ToCamelCase(headerName)
+		headerPointer := buildJSONPointer(pointer, headerName)
+
+		var headerType schema.TypeEncoder
+		var typeSchema *rest.TypeSchema
+		if header.Schema == nil {
+			// header values are strings if the schema is empty
+			headerType = schema.NewNamedType(string(rest.ScalarString))
+			typeSchema = &rest.TypeSchema{Type: "string"}
+			if !header.Required {
+				headerType = schema.NewNullableType(headerType)
+				typeSchema.Nullable = true
+			}
+		} else {
+			var err error
+			headerType, typeSchema, _, err = newOAS3SchemaBuilder(oc.builder, oc.pathKey, rest.InHeader, false).
+				getSchemaTypeFromProxy(header.Schema, !header.Required, append(fieldPaths, fieldName), buildJSONPointer(headerPointer, "schema"))
+			if err != nil {
+				return nil, nil, err
+			}
+			if headerType == nil {
+				continue
+			}
+		}
+		oc.builder.typeUsageCounter.Add(getNamedType(headerType, true, ""), 1)
+
+		field := schema.ObjectField{
+			Type: headerType.Encode(),
+		}
+		if header.Description != "" {
+			field.Description = &header.Description
+		}
+		object.Fields[fieldName] = field
+		if low := header.GoLow(); low != nil {
+			fieldLocations[fieldName] = newSourceLocation(headerPointer, low.KeyNode)
+		}
+		results = append(results, rest.ResponseHeader{
+			Name:      headerName,
+			FieldName: fieldName,
+			Schema:    typeSchema,
+		})
+	}
+	if len(results) == 0 {
+		return nil, nil, nil
 	}
+
+	objectName := utils.StringSliceToPascalCase(fieldPaths)
+	oc.builder.schema.ObjectTypes[objectName] = object
+	oc.builder.report.SourceMap.setObjectType(objectName, newSourceLocation(pointer, nil), fieldLocations)
+	oc.builder.typeUsageCounter.Add(objectName, 1)
+	return schema.NewNamedType(objectName), results, nil
+}
+
+// get the JSON pointer of the operation in the source document with extra tokens
+func (oc *oas3OperationBuilder) getJSONPointer(tokens ...string) string {
+	return buildJSONPointer(buildJSONPointer("", "paths", oc.pathKey, oc.method), tokens...)
 }
@@ -1,9 +1,10 @@
 package internal
 
 import (
-	"errors"
 	"fmt"
 	"log/slog"
+	"path"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/hasura/ndc-rest-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
 )
 
 type oas3SchemaBuilder struct {
@@ -30,9 +32,9 @@ func newOAS3SchemaBuilder(builder *OAS3Builder, apiPath string, location rest.Pa
 }
 
 // get and convert an OpenAPI data type to a NDC type
-func (oc *oas3SchemaBuilder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nullable bool, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
+func (oc *oas3SchemaBuilder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProxy, nullable bool, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
 	if schemaProxy == nil {
-		return nil, nil, false, errParameterSchemaEmpty(fieldPaths)
+		return nil, nil, false, errParameterSchemaEmpty(fieldPaths, pointer)
 	}
 	innerSchema := schemaProxy.Schema()
 	if innerSchema == nil {
@@ -46,16 +48,23 @@ func (oc *oas3SchemaBuilder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProx
 
 	rawRefName := schemaProxy.GetReference()
 	if rawRefName == "" {
-		ndcType, typeSchema, isRef, err = oc.getSchemaType(innerSchema, fieldPaths)
+		ndcType, typeSchema, isRef, err = oc.getSchemaType(innerSchema, fieldPaths, pointer)
 		if err != nil {
 			return nil, nil, false, err
 		}
 	} else if typeCache, ok := oc.builder.schemaCache[rawRefName]; ok {
 		isRef = true
 		ndcType = typeCache.Schema
-		typeSchema = &rest.TypeSchema{
-			Type:        typeCache.Name,
-			Description: innerSchema.Description,
+		if typeCache.TypeSchema != nil {
+			cachedSchema := *typeCache.TypeSchema
+			cachedSchema.Description = innerSchema.Description
+			typeSchema = &cachedSchema
+		} else {
+			typeSchema = &rest.TypeSchema{
+				Type:          typeCache.Name,
+				Description:   innerSchema.Description,
+				Discriminator: oc.builder.discriminators[typeCache.Name],
+			}
 		}
 	} else {
 		// return early object from ref
@@ -69,20 +78,26 @@ func (oc *oas3SchemaBuilder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProx
 
 		_, ok := oc.builder.schema.ObjectTypes[schemaName]
 		if !ok {
-			ndcType, typeSchema, _, err = oc.getSchemaType(innerSchema, []string{refName})
+			ndcType, typeSchema, _, err = oc.getSchemaType(innerSchema, []string{refName}, getRefJSONPointer(rawRefName))
 			if err != nil {
 				return nil, nil, false, err
 			}
 			typeSchema.Description = innerSchema.Description
-			oc.builder.schemaCache[rawRefName] = SchemaInfoCache{
+			cache := SchemaInfoCache{
 				Name:   schemaName,
 				Schema: ndcType,
 			}
+			if typeSchema.MapEntries {
+				cachedSchema := *typeSchema
+				cache.TypeSchema = &cachedSchema
+			}
+			oc.builder.schemaCache[rawRefName] = cache
 		} else {
 			ndcType = schema.NewNamedType(schemaName)
 			typeSchema = &rest.TypeSchema{
-				Type:        schemaName,
-				Description: innerSchema.Description,
+				Type:          schemaName,
+				Description:   innerSchema.Description,
+				Discriminator: oc.builder.discriminators[schemaName],
 			}
 		}
 
@@ -102,26 +117,54 @@ func (oc *oas3SchemaBuilder) getSchemaTypeFromProxy(schemaProxy *base.SchemaProx
 }
 
 // get and convert an OpenAPI data type to a NDC type
-func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
+func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
 
 	if typeSchema == nil {
-		return nil, nil, false, errParameterSchemaEmpty(fieldPaths)
+		return nil, nil, false, errParameterSchemaEmpty(fieldPaths, pointer)
+	}
+
+	if normalizedSchema, isNullable := normalizeSchemaTypes(typeSchema); normalizedSchema != typeSchema {
+		result, typeResult, isRef, err := oc.getSchemaType(normalizedSchema, fieldPaths, pointer)
+		if err != nil || result == nil || !isNullable {
+			return result, typeResult, isRef, err
+		}
+		typeResult.Nullable = true
+		if !isNullableType(result) {
+			result = schema.NewNullableType(result)
+		}
+		return result, typeResult, isRef, nil
 	}
 
 	nullable := typeSchema.Nullable != nil && *typeSchema.Nullable
+	if typeSchema.Discriminator != nil && typeSchema.Discriminator.PropertyName != "" && (len(typeSchema.OneOf) > 1 || len(typeSchema.AnyOf) > 1) {
+		schemaProxies, keyword := typeSchema.OneOf, "oneOf"
+		if len(schemaProxies) == 0 {
+			schemaProxies, keyword = typeSchema.AnyOf, "anyOf"
+		}
+		enc, ty, err := oc.buildDiscriminatedSchemaType(typeSchema, schemaProxies, nullable, fieldPaths, buildJSONPointer(pointer, keyword))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if enc != nil {
+			return enc, ty, false, nil
+		}
+	}
+
 	if len(typeSchema.AllOf) > 0 {
-		enc, ty, isRef, err := oc.buildAllOfAnyOfSchemaType(typeSchema.AllOf, nullable, fieldPaths)
+		enc, ty, isRef, err := oc.buildAllOfAnyOfSchemaType(typeSchema.AllOf, nullable, fieldPaths, buildJSONPointer(pointer, "allOf"))
 		if err != nil {
 			return nil, nil, false, err
 		}
 		if ty != nil {
+			// keep validation keywords that are siblings of the allOf wrapper
+			ty = mergeSchemaKeywords(ty, typeSchema)
 			ty.Description = typeSchema.Description
 		}
 		return enc, ty, isRef, nil
 	}
 
 	if len(typeSchema.AnyOf) > 0 {
-		enc, ty, isRef, err := oc.buildAllOfAnyOfSchemaType(typeSchema.AnyOf, true, fieldPaths)
+		enc, ty, isRef, err := oc.buildAllOfAnyOfSchemaType(typeSchema.AnyOf, true, fieldPaths, buildJSONPointer(pointer, "anyOf"))
 		if err != nil {
 			return nil, nil, false, err
 		}
@@ -133,7 +176,7 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 
 	oneOfLength := len(typeSchema.OneOf)
 	if oneOfLength == 1 {
-		enc, ty, isRef, err := oc.getSchemaTypeFromProxy(typeSchema.OneOf[0], nullable, fieldPaths)
+		enc, ty, isRef, err := oc.getSchemaTypeFromProxy(typeSchema.OneOf[0], nullable, fieldPaths, buildJSONPointer(pointer, "oneOf", "0"))
 		if err != nil {
 			return nil, nil, false, err
 		}
@@ -143,9 +186,24 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 		return enc, ty, isRef, nil
 	}
 
+	if oc.builder.TypedMaps && oneOfLength == 0 && isTypedMapSchema(typeSchema) {
+		enc, ty, err := oc.buildMapEntriesType(typeSchema, fieldPaths, pointer)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if enc != nil {
+			return enc, ty, false, nil
+		}
+	}
+
 	var typeResult *rest.TypeSchema
 	var isRef bool
 	if oneOfLength > 0 || (typeSchema.AdditionalProperties != nil && (typeSchema.AdditionalProperties.B || typeSchema.AdditionalProperties.A != nil)) {
+		if oneOfLength > 0 {
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, "oneOf with many schemas")
+		} else {
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, "object with additionalProperties")
+		}
 		typeResult = createSchemaFromOpenAPISchema(typeSchema, string(rest.ScalarJSON))
 		return oc.builder.buildScalarJSON(), typeResult, false, nil
 	}
@@ -153,14 +211,21 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 	var result schema.TypeEncoder
 	if len(typeSchema.Type) == 0 {
 		if oc.builder.Strict {
-			return nil, nil, false, errParameterSchemaEmpty(fieldPaths)
+			return nil, nil, false, errParameterSchemaEmpty(fieldPaths, pointer)
 		}
+		oc.builder.report.addJSONFallback(fieldPaths, pointer, "schema without type")
 		result = oc.builder.buildScalarJSON()
 		typeResult = createSchemaFromOpenAPISchema(typeSchema, string(rest.ScalarJSON))
 	} else if len(typeSchema.Type) > 1 || isPrimitiveScalar(typeSchema.Type[0]) {
-		scalarName := getScalarFromType(oc.builder.schema, typeSchema.Type, typeSchema.Format, typeSchema.Enum, oc.builder.trimPathPrefix(oc.apiPath), fieldPaths)
+		if len(typeSchema.Type) > 1 {
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, fmt.Sprintf("multiple types %v", typeSchema.Type))
+		}
+		enumNodes := getSchemaEnum(typeSchema)
+		scalarName := getScalarFromType(oc.builder.schema, oc.builder.report, oc.builder.ConvertOptions, typeSchema.Type, getSchemaFormat(typeSchema), enumNodes, typeSchema.Extensions, oc.builder.trimPathPrefix(oc.apiPath), fieldPaths)
+		oc.builder.report.SourceMap.setEnumScalar(scalarName, len(enumNodes), newSourceLocation(pointer, getSchemaNode(typeSchema)))
 		result = schema.NewNamedType(scalarName)
 		typeResult = createSchemaFromOpenAPISchema(typeSchema, scalarName)
+		typeResult.Representation = oc.builder.getNumberRepresentation(scalarName, typeSchema.Type[0])
 	} else {
 		typeName := typeSchema.Type[0]
 		typeResult = createSchemaFromOpenAPISchema(typeSchema, typeName)
@@ -173,6 +238,7 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 					return nil, nil, false, nil
 				}
 				// treat no-property objects as a JSON scalar
+				oc.builder.report.addJSONFallback(fieldPaths, pointer, "object without properties")
 				return oc.builder.buildScalarJSON(), &rest.TypeSchema{Type: string(rest.ScalarJSON)}, false, nil
 			}
 
@@ -192,6 +258,7 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 			}
 
 			typeResult.Properties = make(map[string]rest.TypeSchema)
+			fieldLocations := make(map[string]SourceLocation)
 			for prop := typeSchema.Properties.First(); prop != nil; prop = prop.Next() {
 				propName := prop.Key()
 				oc.builder.Logger.Debug(
@@ -199,13 +266,15 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 					slog.String("name", propName),
 					slog.Any("field", fieldPaths))
 				nullable := !slices.Contains(typeSchema.Required, propName)
-				propType, propApiSchema, _, err := oc.getSchemaTypeFromProxy(prop.Value(), nullable, append(fieldPaths, propName))
+				propPointer := buildJSONPointer(pointer, "properties", propName)
+				propType, propApiSchema, _, err := oc.getSchemaTypeFromProxy(prop.Value(), nullable, append(fieldPaths, propName), propPointer)
 				if err != nil {
 					return nil, nil, false, err
 				}
 				if propType == nil {
 					continue
 				}
+				fieldLocations[propName] = newSourceLocation(propPointer, getSchemaProxyNode(prop.Value()))
 				oc.builder.typeUsageCounter.Add(getNamedType(propType, true, ""), 1)
 				objField := schema.ObjectField{
 					Type: propType.Encode(),
@@ -226,6 +295,8 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 					writeObject.Fields[propName] = objField
 				}
 			}
+			objectLocation := newSourceLocation(pointer, getSchemaNode(typeSchema))
+			oc.builder.report.SourceMap.setObjectType(refName, objectLocation, fieldLocations)
 			if len(readObject.Fields) == 0 && len(writeObject.Fields) == 0 {
 				oc.builder.schema.ObjectTypes[refName] = object
 				result = schema.NewNamedType(refName)
@@ -237,6 +308,7 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 				writeRefName := formatWriteObjectName(refName)
 				oc.builder.schema.ObjectTypes[refName] = readObject
 				oc.builder.schema.ObjectTypes[writeRefName] = writeObject
+				oc.builder.report.SourceMap.copyObjectType(refName, writeRefName)
 				if oc.writeMode {
 					result = schema.NewNamedType(writeRefName)
 				} else {
@@ -244,23 +316,45 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 				}
 			}
 		case "array":
+			if (typeSchema.Items == nil || typeSchema.Items.A == nil) && len(typeSchema.PrefixItems) > 0 {
+				itemType, itemSchema, err := oc.buildPrefixItemsType(typeSchema.PrefixItems, fieldPaths, buildJSONPointer(pointer, "prefixItems"))
+				if err != nil {
+					return nil, nil, false, err
+				}
+				result = schema.NewArrayType(itemType)
+				typeResult.Items = itemSchema
+				break
+			}
 			if typeSchema.Items == nil || typeSchema.Items.A == nil {
-				return nil, nil, false, errors.New("array item is empty")
+				return nil, nil, false, errArrayItemEmpty(fieldPaths, pointer)
 			}
 
-			itemName := getSchemaRefTypeNameV3(typeSchema.Items.A.GetReference())
-			if itemName != "" {
+			itemRef := typeSchema.Items.A.GetReference()
+			itemName := getSchemaRefTypeNameV3(itemRef)
+			if itemName != "" && isSchemaDefsRef(itemRef) {
+				// definitions in $defs aren't converted with component schemas, so the item type is built from the reference
+				itemType, itemSchema, _, err := oc.getSchemaTypeFromProxy(typeSchema.Items.A, false, fieldPaths, buildJSONPointer(pointer, "items"))
+				if err != nil {
+					return nil, nil, false, err
+				}
+				if itemType != nil {
+					result = schema.NewArrayType(itemType)
+					typeResult.Items = itemSchema
+				}
+			} else if itemName != "" {
 				result = schema.NewArrayType(schema.NewNamedType(utils.ToPascalCase(itemName)))
 			} else {
 				itemSchemaA := typeSchema.Items.A.Schema()
 				if itemSchemaA != nil {
-					itemSchema, propType, _isRef, err := oc.getSchemaType(itemSchemaA, fieldPaths)
+					itemPointer := buildJSONPointer(pointer, "items")
+					itemSchema, propType, _isRef, err := oc.getSchemaType(itemSchemaA, fieldPaths, itemPointer)
 					if err != nil {
 						return nil, nil, isRef, err
 					}
 					if itemSchema != nil {
 						result = schema.NewArrayType(itemSchema)
 					} else {
+						oc.builder.report.addJSONFallback(fieldPaths, itemPointer, "array item without type")
 						result = schema.NewArrayType(oc.builder.buildScalarJSON())
 					}
 
@@ -273,23 +367,45 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 				return nil, nil, false, fmt.Errorf("cannot parse type reference name: %s", typeSchema.Items.A.GetReference())
 			}
 		default:
-			return nil, nil, false, fmt.Errorf("unsupported schema type %s", typeName)
+			return nil, nil, false, errUnsupportedSchemaType(typeName, pointer)
 		}
 	}
 
 	return result, typeResult, isRef, nil
 }
 
+// convert prefixItems of OpenAPI 3.1 tuple schemas to the item type of the array.
+// Items of the tuple must have the same type, otherwise the item type falls back to JSON
+func (oc *oas3SchemaBuilder) buildPrefixItemsType(schemaProxies []*base.SchemaProxy, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+	var itemType schema.TypeEncoder
+	var itemSchema *rest.TypeSchema
+	for i, proxy := range schemaProxies {
+		enc, ty, _, err := oc.getSchemaTypeFromProxy(proxy, false, fieldPaths, buildJSONPointer(pointer, fmt.Sprint(i)))
+		if err != nil {
+			return nil, nil, err
+		}
+		if enc == nil || (itemType != nil && !reflect.DeepEqual(itemType.Encode(), enc.Encode())) {
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, "prefixItems with different types")
+			return oc.builder.buildScalarJSON(), &rest.TypeSchema{Type: string(rest.ScalarJSON)}, nil
+		}
+		if itemType == nil {
+			itemType = enc
+			itemSchema = ty
+		}
+	}
+	return itemType, itemSchema, nil
+}
+
 // Support converting allOf and anyOf to object types with merge strategy
-func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.SchemaProxy, nullable bool, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
+func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.SchemaProxy, nullable bool, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, bool, error) {
 	proxies, mergedType, isNullable := evalSchemaProxiesSlice(schemaProxies, oc.location)
 	nullable = nullable || isNullable
 
 	if mergedType != nil {
-		return oc.getSchemaType(mergedType, fieldPaths)
+		return oc.getSchemaType(mergedType, fieldPaths, pointer)
 	}
 	if len(proxies) == 1 {
-		return oc.getSchemaTypeFromProxy(proxies[0], nullable, fieldPaths)
+		return oc.getSchemaTypeFromProxy(proxies[0], nullable, fieldPaths, buildJSONPointer(pointer, fmt.Sprint(slices.Index(schemaProxies, proxies[0]))))
 	}
 	readObject := schema.ObjectType{
 		Fields: schema.ObjectTypeFields{},
@@ -301,10 +417,12 @@ func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.Sch
 		Type:       "object",
 		Properties: map[string]rest.TypeSchema{},
 	}
+	fieldLocations := map[string]SourceLocation{}
 
 	for i, item := range proxies {
 		itemFieldPaths := append(fieldPaths, fmt.Sprint(i))
-		enc, ty, isRef, err := oc.getSchemaTypeFromProxy(item, nullable, itemFieldPaths)
+		itemPointer := buildJSONPointer(pointer, fmt.Sprint(slices.Index(schemaProxies, item)))
+		enc, ty, isRef, err := oc.getSchemaTypeFromProxy(item, nullable, itemFieldPaths, itemPointer)
 		if err != nil {
 			return nil, nil, false, err
 		}
@@ -323,6 +441,7 @@ func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.Sch
 				delete(oc.builder.schema.ObjectTypes, writeName)
 				delete(oc.builder.schema.ScalarTypes, name)
 			}
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, fmt.Sprintf("%s with non-object schemas", path.Base(pointer)))
 			// TODO: should we keep the original anyOf or allOf type schema
 			ty = &rest.TypeSchema{
 				Type:        string(rest.ScalarJSON),
@@ -332,6 +451,13 @@ func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.Sch
 			return oc.builder.buildScalarJSON(), ty, false, nil
 		}
 
+		if sm := oc.builder.report.SourceMap; sm != nil {
+			for key, location := range sm.ObjectTypes[name].Fields {
+				if _, ok := fieldLocations[key]; !ok {
+					fieldLocations[key] = location
+				}
+			}
+		}
 		readObj, ok := oc.builder.schema.ObjectTypes[name]
 		if ok {
 			if readObject.Description == nil && readObj.Description != nil {
@@ -367,11 +493,14 @@ func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.Sch
 
 	refName := utils.ToPascalCase(strings.Join(fieldPaths, " "))
 	writeRefName := formatWriteObjectName(refName)
+	objectLocation := newSourceLocation(pointer, nil)
 	if len(readObject.Fields) > 0 {
 		oc.builder.schema.ObjectTypes[refName] = readObject
+		oc.builder.report.SourceMap.setObjectType(refName, objectLocation, fieldLocations)
 	}
 	if len(writeObject.Fields) > 0 {
 		oc.builder.schema.ObjectTypes[writeRefName] = writeObject
+		oc.builder.report.SourceMap.setObjectType(writeRefName, objectLocation, fieldLocations)
 	}
 
 	if oc.writeMode && len(writeObject.Fields) > 0 {
@@ -385,3 +514,190 @@ func (oc *oas3SchemaBuilder) buildAllOfAnyOfSchemaType(schemaProxies []*base.Sch
 	}
 	return schema.NewNamedType(refName), typeSchema, false, nil
 }
+
+// Convert discriminated oneOf and anyOf schemas to an object type that merges fields of all variants.
+// Variant-specific fields are nullable and the discriminator field is an enum scalar.
+// Returns nil if any variant isn't an object or its discriminator value can't be resolved
+func (oc *oas3SchemaBuilder) buildDiscriminatedSchemaType(baseSchema *base.Schema, schemaProxies []*base.SchemaProxy, nullable bool, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+	discriminator := baseSchema.Discriminator
+	propertyName := discriminator.PropertyName
+	values := make([]string, len(schemaProxies))
+	for i, proxy := range schemaProxies {
+		values[i] = getDiscriminatorValue(discriminator, proxy)
+		if values[i] == "" {
+			oc.builder.report.addJSONFallback(fieldPaths, buildJSONPointer(pointer, fmt.Sprint(i)), "discriminator value of the variant can't be resolved")
+			return nil, nil, nil
+		}
+	}
+
+	readObject := schema.ObjectType{
+		Fields: schema.ObjectTypeFields{},
+	}
+	writeObject := schema.ObjectType{
+		Fields: schema.ObjectTypeFields{},
+	}
+	fieldCounts := map[string]int{}
+	fieldLocations := map[string]SourceLocation{}
+	if baseSchema.Description != "" {
+		readObject.Description = &baseSchema.Description
+		writeObject.Description = &baseSchema.Description
+	}
+	typeSchema := &rest.TypeSchema{
+		Description: baseSchema.Description,
+		Properties:  map[string]rest.TypeSchema{},
+		Discriminator: &rest.Discriminator{
+			PropertyName: propertyName,
+		},
+	}
+	var inlineObjects []string
+
+	for i, proxy := range schemaProxies {
+		itemPointer := buildJSONPointer(pointer, fmt.Sprint(i))
+		enc, ty, isRef, err := oc.getSchemaTypeFromProxy(proxy, false, append(fieldPaths, values[i]), itemPointer)
+		if err != nil {
+			return nil, nil, err
+		}
+		if enc == nil {
+			oc.builder.report.addJSONFallback(fieldPaths, itemPointer, "discriminator variant without type")
+			return nil, nil, nil
+		}
+		name := getNamedType(enc, false, "")
+		writeName := formatWriteObjectName(name)
+		readObj, ok := oc.builder.schema.ObjectTypes[name]
+		if !isRef {
+			inlineObjects = append(inlineObjects, name, writeName)
+		}
+		if !ok {
+			for _, key := range inlineObjects {
+				delete(oc.builder.schema.ObjectTypes, key)
+			}
+			oc.builder.report.addJSONFallback(fieldPaths, pointer, fmt.Sprintf("%s with non-object schemas", path.Base(pointer)))
+			return nil, nil, nil
+		}
+
+		variant := rest.DiscriminatorVariant{
+			Value: values[i],
+		}
+		if isRef {
+			variant.Type = name
+		}
+		writeObj, hasWriteObject := oc.builder.schema.ObjectTypes[writeName]
+		if !hasWriteObject {
+			writeObj = readObj
+		}
+		if readObject.Description == nil && readObj.Description != nil {
+			readObject.Description = readObj.Description
+			writeObject.Description = readObj.Description
+		}
+		variantFields := map[string]bool{}
+		for key, field := range readObj.Fields {
+			variantFields[key] = true
+			if _, ok := readObject.Fields[key]; !ok {
+				readObject.Fields[key] = field
+			}
+		}
+		for key, field := range writeObj.Fields {
+			variantFields[key] = true
+			if _, ok := writeObject.Fields[key]; !ok {
+				writeObject.Fields[key] = field
+			}
+		}
+		delete(variantFields, propertyName)
+		for _, key := range getSortedKeys(variantFields) {
+			variant.Properties = append(variant.Properties, key)
+			fieldCounts[key]++
+		}
+		if ty != nil {
+			for key, prop := range ty.Properties {
+				if _, ok := typeSchema.Properties[key]; !ok && key != propertyName {
+					typeSchema.Properties[key] = prop
+				}
+			}
+		}
+		if sm := oc.builder.report.SourceMap; sm != nil {
+			for key, location := range sm.ObjectTypes[name].Fields {
+				if _, ok := fieldLocations[key]; !ok {
+					fieldLocations[key] = location
+				}
+			}
+		}
+		typeSchema.Discriminator.Variants = append(typeSchema.Discriminator.Variants, variant)
+	}
+	for _, key := range inlineObjects {
+		delete(oc.builder.schema.ObjectTypes, key)
+	}
+
+	// fields that don't exist in all variants are nullable
+	for key, count := range fieldCounts {
+		if count == len(schemaProxies) {
+			continue
+		}
+		for _, object := range []schema.ObjectType{readObject, writeObject} {
+			field, ok := object.Fields[key]
+			if !ok {
+				continue
+			}
+			if !isNullableType(field.Type.Interface()) {
+				field.Type = schema.NewNullableType(field.Type.Interface()).Encode()
+				object.Fields[key] = field
+			}
+		}
+		if prop, ok := typeSchema.Properties[key]; ok {
+			prop.Nullable = true
+			typeSchema.Properties[key] = prop
+		}
+	}
+
+	// create the enum scalar of the discriminator after all variants are resolved
+	// so the scalar isn't orphaned if the schema falls back to JSON
+	enumNodes := make([]*yaml.Node, len(values))
+	for i, value := range values {
+		enumNodes[i] = &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+	enumName := getScalarFromType(oc.builder.schema, oc.builder.report, oc.builder.ConvertOptions, []string{"string"}, "", enumNodes, nil, oc.builder.trimPathPrefix(oc.apiPath), append(fieldPaths, propertyName))
+	oc.builder.report.SourceMap.setEnumScalar(enumName, len(enumNodes), newSourceLocation(pointer, nil))
+
+	discriminatorField := schema.ObjectField{
+		Type: schema.NewNamedType(enumName).Encode(),
+	}
+	readObject.Fields[propertyName] = discriminatorField
+	writeObject.Fields[propertyName] = discriminatorField
+	typeSchema.Properties[propertyName] = rest.TypeSchema{
+		Type: enumName,
+		Enum: decodeEnumNodes(enumNodes, "string"),
+	}
+
+	refName := utils.StringSliceToPascalCase(fieldPaths)
+	writeRefName := formatWriteObjectName(refName)
+	objectLocation := newSourceLocation(pointer, nil)
+	oc.builder.schema.ObjectTypes[refName] = readObject
+	oc.builder.report.SourceMap.setObjectType(refName, objectLocation, fieldLocations)
+	addObjectFieldsUsage(oc.builder.typeUsageCounter, readObject)
+	if !reflect.DeepEqual(readObject, writeObject) {
+		oc.builder.schema.ObjectTypes[writeRefName] = writeObject
+		oc.builder.report.SourceMap.setObjectType(writeRefName, objectLocation, fieldLocations)
+		addObjectFieldsUsage(oc.builder.typeUsageCounter, writeObject)
+		if oc.writeMode {
+			refName = writeRefName
+		}
+	}
+	typeSchema.Type = refName
+	oc.builder.discriminators[refName] = typeSchema.Discriminator
+
+	var result schema.TypeEncoder = schema.NewNamedType(refName)
+	if nullable {
+		typeSchema.Nullable = true
+		result = schema.NewNullableType(result)
+	}
+	return result, typeSchema, nil
+}
+
+// build the array type of key-value entries from a typed map schema
+func (oc *oas3SchemaBuilder) buildMapEntriesType(typeSchema *base.Schema, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+	valueType, valueSchema, _, err := oc.getSchemaTypeFromProxy(typeSchema.AdditionalProperties.A, false, append(fieldPaths, "value"), buildJSONPointer(pointer, "additionalProperties"))
+	if err != nil || valueType == nil {
+		return nil, nil, err
+	}
+	result, typeResult := buildMapEntriesType(oc.builder.schema, oc.builder.typeUsageCounter, oc.builder.report, typeSchema, valueType, valueSchema, fieldPaths, pointer)
+	return result, typeResult, nil
+}
@@ -0,0 +1,196 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// ConversionReport represents the structured report of lossy decisions during the conversion
+type ConversionReport struct {
+	// Operations that aren't converted to functions or procedures
+	SkippedOperations []SkippedOperation `json:"skippedOperations" yaml:"skippedOperations"`
+	// Schemas that are converted to the arbitrary JSON scalar
+	JSONFallbacks []JSONFallback `json:"jsonFallbacks" yaml:"jsonFallbacks"`
+	// Name collisions that were resolved by renaming
+	NameCollisions []NameCollision `json:"nameCollisions" yaml:"nameCollisions"`
+	// Locations of generated elements in the source document. Only available if the SourceMap option is enabled
+	SourceMap *SourceMap `json:"sourceMap,omitempty" yaml:"sourceMap,omitempty"`
+}
+
+// SkippedOperation represents an API operation that isn't converted
+type SkippedOperation struct {
+	Path        string `json:"path" yaml:"path"`
+	Method      string `json:"method" yaml:"method"`
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	// JSON pointer of the operation in the source document
+	Pointer string `json:"pointer" yaml:"pointer"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// JSONFallback represents a schema that is converted to the arbitrary JSON scalar
+type JSONFallback struct {
+	// The field path of the schema, e.g. Pet.metadata
+	Name string `json:"name" yaml:"name"`
+	// JSON pointer of the schema in the source document
+	Pointer string `json:"pointer" yaml:"pointer"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// NameCollision represents a name collision that was resolved by renaming
+type NameCollision struct {
+	// The kind of the element, e.g. argument, scalar type
+	Kind string `json:"kind" yaml:"kind"`
+	// The original name
+	Name string `json:"name" yaml:"name"`
+	// The name after resolving the collision
+	ResolvedName string `json:"resolvedName" yaml:"resolvedName"`
+	// The field path or operation that owns the element
+	Source string `json:"source" yaml:"source"`
+}
+
+// OperationError represents an error of an API operation that is skipped in tolerant mode
+type OperationError struct {
+	Path        string
+	Method      string
+	OperationID string
+	// JSON pointer of the operation in the source document
+	Pointer string
+	Err     error
+}
+
+// Error implements the error interface
+func (oe OperationError) Error() string {
+	return fmt.Sprintf("%s %s (%s): %s", strings.ToUpper(oe.Method), oe.Path, oe.Pointer, oe.Err)
+}
+
+// Unwrap returns the original error
+func (oe OperationError) Unwrap() error {
+	return oe.Err
+}
+
+// NewConversionReport creates an empty ConversionReport instance
+func NewConversionReport() *ConversionReport {
+	return &ConversionReport{
+		SkippedOperations: []SkippedOperation{},
+		JSONFallbacks:     []JSONFallback{},
+		NameCollisions:    []NameCollision{},
+	}
+}
+
+// IsEmpty checks if the report doesn't have any item
+func (cr ConversionReport) IsEmpty() bool {
+	return len(cr.SkippedOperations) == 0 && len(cr.JSONFallbacks) == 0 && len(cr.NameCollisions) == 0
+}
+
+// String implements the fmt.Stringer interface. The report is printed as summary tables
+func (cr ConversionReport) String() string {
+	if cr.IsEmpty() {
+		return "No skipped operation, JSON fallback or name collision.\n"
+	}
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	sections := 0
+	writeHeader := func(title string, count int, columns string) {
+		if sections > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		sections++
+		_, _ = fmt.Fprintf(w, "%s (%d):\n", title, count)
+		_, _ = fmt.Fprintln(w, columns)
+	}
+	if len(cr.SkippedOperations) > 0 {
+		writeHeader("Skipped operations", len(cr.SkippedOperations), "  METHOD\tPATH\tOPERATION ID\tREASON")
+		for _, item := range cr.SkippedOperations {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", strings.ToUpper(item.Method), item.Path, item.OperationID, item.Reason)
+		}
+	}
+	if len(cr.JSONFallbacks) > 0 {
+		writeHeader("JSON fallbacks", len(cr.JSONFallbacks), "  NAME\tPOINTER\tREASON")
+		for _, item := range cr.JSONFallbacks {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", item.Name, item.Pointer, item.Reason)
+		}
+	}
+	if len(cr.NameCollisions) > 0 {
+		writeHeader("Name collisions", len(cr.NameCollisions), "  KIND\tNAME\tRESOLVED NAME\tSOURCE")
+		for _, item := range cr.NameCollisions {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", item.Kind, item.Name, item.ResolvedName, item.Source)
+		}
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+func (cr *ConversionReport) addSkippedOperation(path string, method string, operationID string, reason string) {
+	if cr == nil {
+		return
+	}
+	cr.SkippedOperations = append(cr.SkippedOperations, SkippedOperation{
+		Path:        path,
+		Method:      method,
+		OperationID: operationID,
+		Pointer:     buildJSONPointer("", "paths", path, method),
+		Reason:      reason,
+	})
+}
+
+func (cr *ConversionReport) addSkippedWebhook(name string, method string, operationID string) {
+	if cr == nil {
+		return
+	}
+	cr.SkippedOperations = append(cr.SkippedOperations, SkippedOperation{
+		Path:        name,
+		Method:      method,
+		OperationID: operationID,
+		Pointer:     buildJSONPointer("", "webhooks", name, method),
+		Reason:      "webhooks are not supported",
+	})
+}
+
+func (cr *ConversionReport) addJSONFallback(fieldPaths []string, pointer string, reason string) {
+	if cr == nil {
+		return
+	}
+	item := JSONFallback{
+		Name:    strings.Join(fieldPaths, "."),
+		Pointer: pointer,
+		Reason:  reason,
+	}
+	if !slices.Contains(cr.JSONFallbacks, item) {
+		cr.JSONFallbacks = append(cr.JSONFallbacks, item)
+	}
+}
+
+func (cr *ConversionReport) addNameCollision(kind string, name string, resolvedName string, source string) {
+	if cr == nil {
+		return
+	}
+	item := NameCollision{
+		Kind:         kind,
+		Name:         name,
+		ResolvedName: resolvedName,
+		Source:       source,
+	}
+	if !slices.Contains(cr.NameCollisions, item) {
+		cr.NameCollisions = append(cr.NameCollisions, item)
+	}
+}
+
+// buildJSONPointer appends reference tokens to the base JSON pointer. Tokens are escaped as defined in [RFC 6901]
+//
+// [RFC 6901]: https://datatracker.ietf.org/doc/html/rfc6901
+func buildJSONPointer(base string, tokens ...string) string {
+	var sb strings.Builder
+	sb.WriteString(base)
+	for _, token := range tokens {
+		sb.WriteRune('/')
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// get the JSON pointer of the referenced schema, e.g. #/components/schemas/Pet -> /components/schemas/Pet
+func getRefJSONPointer(ref string) string {
+	return strings.TrimPrefix(ref, "#")
+}
@@ -0,0 +1,208 @@
+package internal
+
+import (
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// SourceMap maps generated functions, procedures, object types, fields and enum scalars to their locations in the source document
+type SourceMap struct {
+	Collections map[string]SourceLocation   `json:"collections" yaml:"collections"`
+	Functions   map[string]SourceLocation   `json:"functions" yaml:"functions"`
+	Procedures  map[string]SourceLocation   `json:"procedures" yaml:"procedures"`
+	ObjectTypes map[string]ObjectTypeSource `json:"object_types" yaml:"object_types"`
+	ScalarTypes map[string]SourceLocation   `json:"scalar_types" yaml:"scalar_types"`
+}
+
+// SourceLocation represents the location of an element in the source document
+type SourceLocation struct {
+	// JSON pointer of the element
+	Pointer string `json:"pointer" yaml:"pointer"`
+	// The 1-based line number. Zero if unknown
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+	// The 1-based column number. Zero if unknown
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+// ObjectTypeSource represents the location of an object type and its fields in the source document
+type ObjectTypeSource struct {
+	SourceLocation `yaml:",inline"`
+	Fields         map[string]SourceLocation `json:"fields" yaml:"fields"`
+}
+
+// NewSourceMap creates an empty SourceMap instance
+func NewSourceMap() *SourceMap {
+	return &SourceMap{
+		Collections: map[string]SourceLocation{},
+		Functions:   map[string]SourceLocation{},
+		Procedures:  map[string]SourceLocation{},
+		ObjectTypes: map[string]ObjectTypeSource{},
+		ScalarTypes: map[string]SourceLocation{},
+	}
+}
+
+func newSourceLocation(pointer string, node *yaml.Node) SourceLocation {
+	location := SourceLocation{
+		Pointer: pointer,
+	}
+	if node != nil {
+		location.Line = node.Line
+		location.Column = node.Column
+	}
+	return location
+}
+
+func (sm *SourceMap) setCollection(name string, location SourceLocation) {
+	if sm == nil {
+		return
+	}
+	sm.Collections[name] = location
+}
+
+func (sm *SourceMap) setFunction(name string, location SourceLocation) {
+	if sm == nil {
+		return
+	}
+	sm.Functions[name] = location
+}
+
+func (sm *SourceMap) setProcedure(name string, location SourceLocation) {
+	if sm == nil {
+		return
+	}
+	sm.Procedures[name] = location
+}
+
+func (sm *SourceMap) setObjectType(name string, location SourceLocation, fields map[string]SourceLocation) {
+	if sm == nil {
+		return
+	}
+	sm.ObjectTypes[name] = ObjectTypeSource{
+		SourceLocation: location,
+		Fields:         fields,
+	}
+}
+
+// copy the location of the source object type to the target object type, e.g. write object types
+func (sm *SourceMap) copyObjectType(source string, target string) {
+	if sm == nil {
+		return
+	}
+	if object, ok := sm.ObjectTypes[source]; ok {
+		sm.ObjectTypes[target] = object
+	}
+}
+
+// set the location of the enum scalar. The first location is kept if the scalar is shared
+func (sm *SourceMap) setEnumScalar(name string, enumLength int, location SourceLocation) {
+	if sm == nil || enumLength == 0 {
+		return
+	}
+	if _, ok := sm.ScalarTypes[name]; !ok {
+		sm.ScalarTypes[name] = location
+	}
+}
+
+// remove locations of elements that don't exist in the output schema
+func (sm *SourceMap) prune(schema *rest.NDCRestSchema) {
+	if sm == nil {
+		return
+	}
+	collections := make(map[string]bool)
+	for _, collection := range schema.Collections {
+		collections[collection.Name] = true
+	}
+	for key := range sm.Collections {
+		if !collections[key] {
+			delete(sm.Collections, key)
+		}
+	}
+	functions := make(map[string]bool)
+	for _, fn := range schema.Functions {
+		functions[fn.Name] = true
+	}
+	for key := range sm.Functions {
+		if !functions[key] {
+			delete(sm.Functions, key)
+		}
+	}
+	procedures := make(map[string]bool)
+	for _, proc := range schema.Procedures {
+		procedures[proc.Name] = true
+	}
+	for key := range sm.Procedures {
+		if !procedures[key] {
+			delete(sm.Procedures, key)
+		}
+	}
+	for key, object := range sm.ObjectTypes {
+		objectType, ok := schema.ObjectTypes[key]
+		if !ok {
+			delete(sm.ObjectTypes, key)
+			continue
+		}
+		for field := range object.Fields {
+			if _, ok := objectType.Fields[field]; !ok {
+				delete(object.Fields, field)
+			}
+		}
+	}
+	for key := range sm.ScalarTypes {
+		if _, ok := schema.ScalarTypes[key]; !ok {
+			delete(sm.ScalarTypes, key)
+		}
+	}
+}
+
+// get the yaml node of the schema for source locations
+func getSchemaNode(typeSchema *base.Schema) *yaml.Node {
+	if typeSchema == nil || typeSchema.GoLow() == nil {
+		return nil
+	}
+	return typeSchema.GoLow().RootNode
+}
+
+// get the yaml node of the schema proxy for source locations
+func getSchemaProxyNode(schemaProxy *base.SchemaProxy) *yaml.Node {
+	if schemaProxy == nil || schemaProxy.GoLow() == nil {
+		return nil
+	}
+	return schemaProxy.GoLow().GetValueNode()
+}
+
+// get the yaml key node of the Swagger 2.0 operation for source locations.
+// Swagger 2.0 operations don't keep their key nodes, so the node is looked up from the low-level path item
+func getOperationNodeV2(pathItem *v2.PathItem, method string) *yaml.Node {
+	if pathItem == nil || pathItem.GoLow() == nil {
+		return nil
+	}
+	low := pathItem.GoLow()
+	switch method {
+	case "get":
+		return low.Get.KeyNode
+	case "post":
+		return low.Post.KeyNode
+	case "put":
+		return low.Put.KeyNode
+	case "patch":
+		return low.Patch.KeyNode
+	case "delete":
+		return low.Delete.KeyNode
+	case "head":
+		return low.Head.KeyNode
+	case "options":
+		return low.Options.KeyNode
+	default:
+		return nil
+	}
+}
+
+// get the yaml node of the Swagger 2.0 parameter for source locations
+func getParameterNodeV2(param *v2.Parameter) *yaml.Node {
+	if param == nil || param.GoLow() == nil {
+		return nil
+	}
+	return param.GoLow().Name.KeyNode
+}
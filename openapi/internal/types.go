@@ -5,13 +5,19 @@ import (
 	"regexp"
 
 	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
 )
 
+const operationKindExtension = "x-ndc-operation"
+
+// HTTP methods that aren't converted by default. These operations are converted if they are enabled in the ExtraMethods option
+var extraMethods = []string{"head", "options", "trace"}
+
 var (
 	bracketRegexp         = regexp.MustCompile(`[\{\}]`)
 	schemaRefNameV2Regexp = regexp.MustCompile(`^#/definitions/([a-zA-Z0-9\.\-_]+)$`)
-	schemaRefNameV3Regexp = regexp.MustCompile(`^#/components/schemas/([a-zA-Z0-9\.\-_]+)$`)
+	schemaRefNameV3Regexp = regexp.MustCompile(`^#/components/schemas/(?:([a-zA-Z0-9\.\-_]+)/\$defs/)?([a-zA-Z0-9\.\-_]+)$`)
 )
 
 var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
@@ -25,6 +31,18 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
+	// integer with int8 format
+	rest.ScalarInt8: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationInt8().Encode(),
+	},
+	// integer with int16 or uint8 format
+	rest.ScalarInt16: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationInt16().Encode(),
+	},
 	rest.ScalarInt32: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
@@ -35,6 +53,12 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationInt64().Encode(),
 	},
+	// integer with uint64 format that overflows the signed 64-bit integer
+	rest.ScalarBigInteger: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationBigInteger().Encode(),
+	},
 	rest.ScalarFloat32: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
@@ -45,6 +69,12 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationFloat64().Encode(),
 	},
+	// number or string with decimal format
+	rest.ScalarBigDecimal: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationBigDecimal().Encode(),
+	},
 	rest.ScalarJSON: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
@@ -63,6 +93,24 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationTimestampTZ().Encode(),
 	},
+	// string with date-time format without timezone, e.g. date-time-local
+	rest.ScalarTimestamp: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationTimestamp().Encode(),
+	},
+	// string with time format, e.g. 10:00:00Z
+	rest.ScalarTime: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationString().Encode(),
+	},
+	// string with duration format, e.g. P3D
+	rest.ScalarDuration: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationString().Encode(),
+	},
 	// string with byte format
 	rest.ScalarBytes: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
@@ -85,6 +133,21 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
+	rest.ScalarURIReference: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationString().Encode(),
+	},
+	rest.ScalarHostname: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationString().Encode(),
+	},
+	rest.ScalarPassword: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationString().Encode(),
+	},
 	rest.ScalarUUID: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
@@ -104,10 +167,28 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 	rest.ScalarUnixTime: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
-		Representation:      schema.NewTypeRepresentationInt32().Encode(),
+		Representation:      schema.NewTypeRepresentationInt64().Encode(),
 	},
 }
 
+// formats of default scalar types that can't be inferred from their representations. Used to export scalar types to OpenAPI schemas
+var defaultScalarFormats = map[rest.ScalarName]string{
+	rest.ScalarBigInteger:   "uint64",
+	rest.ScalarBigDecimal:   "decimal",
+	rest.ScalarTime:         "time",
+	rest.ScalarTimestamp:    "date-time-local",
+	rest.ScalarDuration:     "duration",
+	rest.ScalarBinary:       "binary",
+	rest.ScalarUnixTime:     "unix-time",
+	rest.ScalarEmail:        "email",
+	rest.ScalarURI:          "uri",
+	rest.ScalarURIReference: "uri-reference",
+	rest.ScalarHostname:     "hostname",
+	rest.ScalarIPV4:         "ipv4",
+	rest.ScalarIPV6:         "ipv6",
+	rest.ScalarPassword:     "password",
+}
+
 // ConvertOptions represent the common convert options for both OpenAPI v2 and v3
 type ConvertOptions struct {
 	MethodAlias         map[string]string
@@ -116,7 +197,44 @@ type ConvertOptions struct {
 	TrimPrefix          string
 	EnvPrefix           string
 	Strict              bool
-	Logger              *slog.Logger
+	// Skip operations that fail to be converted and collect errors instead of stopping at the first error
+	Tolerant bool
+	// Collect the source map of generated functions, procedures and types to the conversion report
+	SourceMap bool
+	// Include operations which match any of these rules. All operations are included if empty
+	Include []utils.OperationFilter
+	// Exclude operations which match any of these rules
+	Exclude []utils.OperationFilter
+	// Override the NDC operation kind of operations which match these rules, e.g. convert POST search endpoints to functions.
+	// Rules take precedence over the x-ndc-operation extension of the document
+	OperationKinds []utils.OperationKindRule
+	// Map OpenAPI schemas which match the type, format or extension to custom scalar types. The first matched mapping wins
+	ScalarMappings []utils.ScalarMapping
+	// Convert objects with typed additionalProperties and no fixed properties to arrays of key-value entry objects instead of JSON scalars
+	TypedMaps bool
+	// The JSON representation of integers with int64 and uint64 formats. Inferred from the OpenAPI type if empty
+	Int64Representation rest.NumberRepresentation
+	// The JSON representation of numbers with the decimal format. Inferred from the OpenAPI type if empty
+	DecimalRepresentation rest.NumberRepresentation
+	// Wrap results in envelope objects with the headers and body fields if the success response documents headers
+	ResponseHeaders bool
+	// Record responses of all documented status codes. Success responses with different types are merged into a union object
+	// and error payloads are converted to object types
+	StatusCodeResponses bool
+	// Convert operations of extra HTTP methods: head, options and trace. These operations are skipped by default
+	ExtraMethods []string
+	// Detect paginated GET list endpoints and convert them to collections
+	DetectCollections bool
+	// Convert GET operations which match any of these rules to collections
+	Collections []utils.CollectionRule
+	Logger      *slog.Logger
+}
+
+// ExportOptions represent the options to export the NDC REST schema to OpenAPI 3
+type ExportOptions struct {
+	// The title of the API document
+	Title  string
+	Logger *slog.Logger
 }
 
 // TypeUsageCounter tracks the list of reference types and number of usage of them in other models
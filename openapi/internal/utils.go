@@ -3,6 +3,7 @@ package internal
 import (
 	"fmt"
 	"log/slog"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -10,17 +11,143 @@ import (
 	"github.com/hasura/ndc-rest-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
 )
 
+// get the JSON representation of int64, uint64 and decimal values of the scalar.
+// If the option isn't set, the representation is string if the OpenAPI type is string, or empty for the default number
+func (opts *ConvertOptions) getNumberRepresentation(scalarName string, typeName string) rest.NumberRepresentation {
+	var representation rest.NumberRepresentation
+	switch rest.ScalarName(scalarName) {
+	case rest.ScalarInt64, rest.ScalarBigInteger:
+		representation = opts.Int64Representation
+	case rest.ScalarBigDecimal:
+		representation = opts.DecimalRepresentation
+	default:
+		return ""
+	}
+	if representation != "" || typeName == "" {
+		return representation
+	}
+	if typeName == "string" {
+		return rest.NumberRepresentationString
+	}
+	return ""
+}
+
+// validate the representation options of int64, uint64 and decimal values
+func validateNumberRepresentations(opts *ConvertOptions) error {
+	if opts.Int64Representation != "" {
+		if _, err := rest.ParseNumberRepresentation(string(opts.Int64Representation)); err != nil {
+			return fmt.Errorf("int64Representation: %w", err)
+		}
+	}
+	if opts.DecimalRepresentation != "" {
+		if _, err := rest.ParseNumberRepresentation(string(opts.DecimalRepresentation)); err != nil {
+			return fmt.Errorf("decimalRepresentation: %w", err)
+		}
+	}
+	return nil
+}
+
 func applyConvertOptions(opts ConvertOptions) *ConvertOptions {
 	if opts.Logger == nil {
 		opts.Logger = slog.Default()
 	}
 	opts.MethodAlias = getMethodAlias(opts.MethodAlias)
+	methods := make([]string, len(opts.ExtraMethods))
+	for i, method := range opts.ExtraMethods {
+		methods[i] = strings.ToLower(method)
+	}
+	opts.ExtraMethods = methods
 	return &opts
 }
 
+func validateExtraMethods(methods []string) error {
+	for _, method := range methods {
+		if !slices.Contains(extraMethods, method) {
+			return fmt.Errorf("invalid extra method %s. Expected %v", method, extraMethods)
+		}
+	}
+	return nil
+}
+
+// check if the operation of the HTTP method should be converted
+func isMethodEnabled(options *ConvertOptions, method string) bool {
+	return !slices.Contains(extraMethods, method) || slices.Contains(options.ExtraMethods, method)
+}
+
+// check if the operation matches filter rules. Excluded operations are added to the report
+func isOperationIncluded(options *ConvertOptions, report *ConversionReport, input utils.OperationFilterInput) bool {
+	included := utils.IsOperationIncluded(options.Include, options.Exclude, input)
+	if !included {
+		options.Logger.Debug("skipped operation by filter rules",
+			slog.String("path", input.Path),
+			slog.String("method", input.Method),
+			slog.String("operation_id", input.OperationID),
+		)
+		report.addSkippedOperation(input.Path, input.Method, input.OperationID, "excluded by filter rules")
+	}
+	return included
+}
+
+// returns the error in strict mode. In tolerant mode, the operation is skipped and the error is collected
+func handleOperationError(options *ConvertOptions, report *ConversionReport, operationErrors *[]error, pathKey string, method string, operationID string, err error) error {
+	if !options.Tolerant {
+		return err
+	}
+	opErr := OperationError{
+		Path:        pathKey,
+		Method:      method,
+		OperationID: operationID,
+		Pointer:     buildJSONPointer("", "paths", pathKey, method),
+		Err:         err,
+	}
+	options.Logger.Warn("skipped operation due to error",
+		slog.String("path", pathKey),
+		slog.String("method", method),
+		slog.String("operation_id", operationID),
+		slog.String("error", err.Error()),
+	)
+	*operationErrors = append(*operationErrors, opErr)
+	report.addSkippedOperation(pathKey, method, operationID, err.Error())
+	return nil
+}
+
+// get the extension value of the specification object. Returns nil if the extension doesn't exist
+func getExtensionNode(extensions *orderedmap.Map[string, *yaml.Node], key string) *yaml.Node {
+	if extensions == nil {
+		return nil
+	}
+	return extensions.GetOrZero(key)
+}
+
+// get the NDC operation kind of the API operation from config rules and the x-ndc-operation extension.
+// Config rules take precedence over the extension, so the document can be overridden without patching.
+// Operations of safe methods (GET, HEAD, OPTIONS and TRACE) are converted to functions
+// and other methods are converted to procedures by default
+func getOperationKind(options *ConvertOptions, extensionNode *yaml.Node, input utils.OperationFilterInput) (rest.OperationKind, error) {
+	if kind := utils.FindOperationKind(options.OperationKinds, input); kind != "" {
+		return kind, nil
+	}
+	if extensionNode != nil {
+		kind, err := rest.ParseOperationKind(extensionNode.Value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", operationKindExtension, err)
+		}
+		return kind, nil
+	}
+	return getDefaultOperationKind(input.Method), nil
+}
+
+func getDefaultOperationKind(method string) rest.OperationKind {
+	if method == "get" || slices.Contains(extraMethods, method) {
+		return rest.OperationFunction
+	}
+	return rest.OperationProcedure
+}
+
 func buildPathMethodName(apiPath string, method string, options *ConvertOptions) string {
 	if options.TrimPrefix != "" {
 		apiPath = strings.TrimPrefix(apiPath, options.TrimPrefix)
@@ -42,19 +169,169 @@ func getSchemaRefTypeNameV2(name string) string {
 
 func getSchemaRefTypeNameV3(name string) string {
 	result := schemaRefNameV3Regexp.FindStringSubmatch(name)
-	if len(result) < 2 {
+	if len(result) < 3 {
 		return ""
 	}
-	return result[1]
+	// definitions in $defs are namespaced by the parent schema to avoid conflicts with component schemas,
+	// e.g. #/components/schemas/Pet/$defs/Tag is PetTag
+	if result[1] != "" {
+		return utils.StringSliceToPascalCase([]string{result[1], result[2]})
+	}
+	return result[2]
+}
+
+// check if the reference points to a definition in $defs of a component schema, e.g. #/components/schemas/Pet/$defs/Tag
+func isSchemaDefsRef(ref string) bool {
+	return strings.Contains(ref, "/$defs/")
+}
+
+// normalize OpenAPI 3.1 type keywords to the equivalent OpenAPI 3.0 form.
+// The null type is removed from the type array, e.g. [string, "null"], and the type of the const-only schema is inferred from the value.
+// Returns a copy of the schema and true if the schema is nullable
+func normalizeSchemaTypes(typeSchema *base.Schema) (*base.Schema, bool) {
+	nullable := slices.Contains(typeSchema.Type, "null")
+	inferConst := len(typeSchema.Type) == 0 && typeSchema.Const != nil && len(typeSchema.AllOf) == 0 && len(typeSchema.AnyOf) == 0 && len(typeSchema.OneOf) == 0
+	if !nullable && !inferConst {
+		return typeSchema, false
+	}
+
+	result := *typeSchema
+	if nullable {
+		result.Type = slices.DeleteFunc(slices.Clone(typeSchema.Type), func(name string) bool {
+			return name == "null"
+		})
+	}
+	if inferConst {
+		switch typeSchema.Const.Tag {
+		case "!!str":
+			result.Type = []string{"string"}
+		case "!!int":
+			result.Type = []string{"integer"}
+		case "!!float":
+			result.Type = []string{"number"}
+		case "!!bool":
+			result.Type = []string{"boolean"}
+		}
+	}
+	return &result, nullable
+}
+
+// get the format of the schema. OpenAPI 3.1 string schemas use contentEncoding and contentMediaType keywords instead of byte and binary formats
+func getSchemaFormat(typeSchema *base.Schema) string {
+	if typeSchema.Format != "" || !slices.Contains(typeSchema.Type, "string") {
+		return typeSchema.Format
+	}
+	switch getSchemaKeywordValue(typeSchema, "contentEncoding") {
+	case "base64", "base64url":
+		return "base64"
+	case "":
+		if mediaType := getSchemaKeywordValue(typeSchema, "contentMediaType"); mediaType != "" && !strings.HasPrefix(mediaType, "text/") && !strings.Contains(mediaType, "json") {
+			return "binary"
+		}
+	}
+	return ""
 }
 
-func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, enumNodes []*yaml.Node, apiPath string, fieldPaths []string) string {
+// get enum values of the schema. The const keyword is considered as a single-value enum
+func getSchemaEnum(typeSchema *base.Schema) []*yaml.Node {
+	if len(typeSchema.Enum) == 0 && typeSchema.Const != nil {
+		return []*yaml.Node{typeSchema.Const}
+	}
+	return typeSchema.Enum
+}
+
+// decode enum values with their original types. Values of string schemas are always kept as strings
+func decodeEnumNodes(enumNodes []*yaml.Node, typeName string) []any {
+	if len(enumNodes) == 0 {
+		return nil
+	}
+	results := make([]any, 0, len(enumNodes))
+	for _, node := range enumNodes {
+		if node == nil {
+			continue
+		}
+		if typeName == "string" {
+			results = append(results, node.Value)
+			continue
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			value = node.Value
+		}
+		results = append(results, value)
+	}
+	return results
+}
+
+// decode the value of a schema keyword, e.g. default or example. Returns nil if the node is empty
+func decodeNodeValue(node *yaml.Node) any {
+	if node == nil {
+		return nil
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return value
+}
+
+// get the exclusive bound of the schema. The boolean form of OpenAPI 2.0 and 3.0 makes the inclusive bound exclusive
+func getExclusiveBound(exclusive *base.DynamicValue[bool, float64], bound *float64) *float64 {
+	switch {
+	case exclusive == nil:
+		return nil
+	case exclusive.IsB():
+		value := exclusive.B
+		return &value
+	case exclusive.A && bound != nil:
+		value := *bound
+		return &value
+	default:
+		return nil
+	}
+}
+
+// get the value of a scalar keyword that isn't supported by the high-level schema model, e.g. contentEncoding
+func getSchemaKeywordValue(typeSchema *base.Schema, key string) string {
+	node := getSchemaNode(typeSchema)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// decode examples of the schema to JSON values
+func getSchemaExamples(typeSchema *base.Schema) []any {
+	var results []any
+	for _, node := range typeSchema.Examples {
+		if node == nil {
+			continue
+		}
+		var value any
+		if err := node.Decode(&value); err == nil {
+			results = append(results, value)
+		}
+	}
+	return results
+}
+
+func getScalarFromType(sm *rest.NDCRestSchema, report *ConversionReport, options *ConvertOptions, names []string, format string, enumNodes []*yaml.Node, extensions *orderedmap.Map[string, *yaml.Node], apiPath string, fieldPaths []string) string {
 	var scalarName string
 	var scalarType *schema.ScalarType
 
 	if len(names) != 1 {
 		scalarName = "JSON"
 		scalarType = defaultScalarTypes[rest.ScalarJSON]
+	} else if mapping := utils.FindScalarMapping(options.ScalarMappings, names[0], format, getExtensionValueFunc(extensions)); mapping != nil {
+		scalarName = mapping.Scalar
+		scalarType = buildScalarTypeFromMapping(mapping, names[0])
+	} else if len(enumNodes) > 0 && (names[0] == "string" || names[0] == "integer" || names[0] == "number") {
+		return buildEnumScalar(sm, report, enumNodes, apiPath, fieldPaths)
 	} else {
 		switch names[0] {
 		case "boolean":
@@ -65,9 +342,18 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			case "unix-time":
 				scalarName = string(rest.ScalarUnixTime)
 				scalarType = defaultScalarTypes[rest.ScalarUnixTime]
-			case "int64":
+			case "int8":
+				scalarName = string(rest.ScalarInt8)
+				scalarType = defaultScalarTypes[rest.ScalarInt8]
+			case "int16", "uint8":
+				scalarName = string(rest.ScalarInt16)
+				scalarType = defaultScalarTypes[rest.ScalarInt16]
+			case "int64", "uint32":
 				scalarName = string(rest.ScalarInt64)
 				scalarType = defaultScalarTypes[rest.ScalarInt64]
+			case "uint64":
+				scalarName = string(rest.ScalarBigInteger)
+				scalarType = defaultScalarTypes[rest.ScalarBigInteger]
 			default:
 				scalarName = "Int32"
 				scalarType = defaultScalarTypes[rest.ScalarInt32]
@@ -80,6 +366,9 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			case "float":
 				scalarName = string(rest.ScalarFloat32)
 				scalarType = defaultScalarTypes[rest.ScalarFloat32]
+			case "decimal":
+				scalarName = string(rest.ScalarBigDecimal)
+				scalarType = defaultScalarTypes[rest.ScalarBigDecimal]
 			default:
 				scalarName = string(rest.ScalarFloat64)
 				scalarType = defaultScalarTypes[rest.ScalarFloat64]
@@ -88,51 +377,6 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			scalarName = string(rest.ScalarBinary)
 			scalarType = defaultScalarTypes[rest.ScalarBinary]
 		case "string":
-			schemaEnumLength := len(enumNodes)
-			if schemaEnumLength > 0 {
-				enums := make([]string, schemaEnumLength)
-				for i, enum := range enumNodes {
-					enums[i] = enum.Value
-				}
-				scalarType = schema.NewScalarType()
-				scalarType.Representation = schema.NewTypeRepresentationEnum(enums).Encode()
-
-				// build scalar name strategies
-				// 1. combine resource name and field name
-				apiPath = strings.TrimPrefix(apiPath, "/")
-				if apiPath != "" {
-					apiPaths := strings.Split(apiPath, "/")
-					resourceName := fieldPaths[0]
-					if len(apiPaths) > 0 {
-						resourceName = apiPaths[0]
-					}
-					enumName := "Enum"
-					if len(fieldPaths) > 1 {
-						enumName = fieldPaths[len(fieldPaths)-1]
-					}
-
-					scalarName = utils.StringSliceToPascalCase([]string{resourceName, enumName})
-					if canSetEnumToSchema(sm, scalarName, enums) {
-						sm.ScalarTypes[scalarName] = *scalarType
-						return scalarName
-					}
-				}
-
-				// 2. if the scalar type exists, fallback to field paths
-				scalarName = utils.StringSliceToPascalCase(fieldPaths)
-				if canSetEnumToSchema(sm, scalarName, enums) {
-					sm.ScalarTypes[scalarName] = *scalarType
-					return scalarName
-				}
-
-				// 3. Reuse above name with Enum suffix
-				scalarName = fmt.Sprintf("%sEnum", scalarName)
-				if _, ok := sm.ScalarTypes[scalarName]; !ok {
-					sm.ScalarTypes[scalarName] = *scalarType
-				}
-				return scalarName
-			}
-
 			switch format {
 			case "date":
 				scalarName = string(rest.ScalarDate)
@@ -140,6 +384,24 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			case "date-time":
 				scalarName = string(rest.ScalarTimestampTZ)
 				scalarType = defaultScalarTypes[rest.ScalarTimestampTZ]
+			case "date-time-local", "local-date-time":
+				scalarName = string(rest.ScalarTimestamp)
+				scalarType = defaultScalarTypes[rest.ScalarTimestamp]
+			case "time", "time-local", "local-time":
+				scalarName = string(rest.ScalarTime)
+				scalarType = defaultScalarTypes[rest.ScalarTime]
+			case "duration":
+				scalarName = string(rest.ScalarDuration)
+				scalarType = defaultScalarTypes[rest.ScalarDuration]
+			case "int64":
+				scalarName = string(rest.ScalarInt64)
+				scalarType = defaultScalarTypes[rest.ScalarInt64]
+			case "uint64":
+				scalarName = string(rest.ScalarBigInteger)
+				scalarType = defaultScalarTypes[rest.ScalarBigInteger]
+			case "decimal":
+				scalarName = string(rest.ScalarBigDecimal)
+				scalarType = defaultScalarTypes[rest.ScalarBigDecimal]
 			case "byte", "base64":
 				scalarName = string(rest.ScalarBytes)
 				scalarType = defaultScalarTypes[rest.ScalarBytes]
@@ -152,6 +414,18 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			case "uri":
 				scalarName = string(rest.ScalarURI)
 				scalarType = defaultScalarTypes[rest.ScalarURI]
+			case "uri-reference":
+				scalarName = string(rest.ScalarURIReference)
+				scalarType = defaultScalarTypes[rest.ScalarURIReference]
+			case "email", "idn-email":
+				scalarName = string(rest.ScalarEmail)
+				scalarType = defaultScalarTypes[rest.ScalarEmail]
+			case "hostname", "idn-hostname":
+				scalarName = string(rest.ScalarHostname)
+				scalarType = defaultScalarTypes[rest.ScalarHostname]
+			case "password":
+				scalarName = string(rest.ScalarPassword)
+				scalarType = defaultScalarTypes[rest.ScalarPassword]
 			case "ipv4":
 				scalarName = string(rest.ScalarIPV4)
 				scalarType = defaultScalarTypes[rest.ScalarIPV4]
@@ -166,6 +440,7 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 			scalarName = string(rest.ScalarJSON)
 			scalarType = defaultScalarTypes[rest.ScalarJSON]
 		}
+		scalarType = buildNumberScalarType(scalarType, options.getNumberRepresentation(scalarName, ""))
 	}
 
 	if _, ok := sm.ScalarTypes[scalarName]; !ok {
@@ -174,6 +449,98 @@ func getScalarFromType(sm *rest.NDCRestSchema, names []string, format string, en
 	return scalarName
 }
 
+// build the scalar type of int64, uint64 and decimal values with the configured representation
+func buildNumberScalarType(scalarType *schema.ScalarType, representation rest.NumberRepresentation) *schema.ScalarType {
+	result := *scalarType
+	switch representation {
+	case rest.NumberRepresentationString:
+		result.Representation = schema.NewTypeRepresentationString().Encode()
+	case rest.NumberRepresentationBigDecimal:
+		result.Representation = schema.NewTypeRepresentationBigDecimal().Encode()
+	default:
+		return scalarType
+	}
+	return &result
+}
+
+// build the custom scalar type of a scalar mapping. The representation is inferred from the OpenAPI type if empty
+func buildScalarTypeFromMapping(mapping *utils.ScalarMapping, typeName string) *schema.ScalarType {
+	scalarType := schema.NewScalarType()
+	scalarType.Representation = schema.TypeRepresentation{
+		"type": mapping.GetRepresentation(typeName),
+	}
+	return scalarType
+}
+
+// return a function that gets the string value of a specification extension
+func getExtensionValueFunc(extensions *orderedmap.Map[string, *yaml.Node]) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		if extensions == nil {
+			return "", false
+		}
+		node, ok := extensions.Get(name)
+		if !ok || node == nil {
+			return "", false
+		}
+		return node.Value, true
+	}
+}
+
+// build an enum scalar of string, integer or number enums. Scalar names are resolved by strategies to avoid conflicts
+func buildEnumScalar(sm *rest.NDCRestSchema, report *ConversionReport, enumNodes []*yaml.Node, apiPath string, fieldPaths []string) string {
+	enums := make([]string, len(enumNodes))
+	for i, enum := range enumNodes {
+		enums[i] = enum.Value
+	}
+	scalarType := schema.NewScalarType()
+	scalarType.Representation = schema.NewTypeRepresentationEnum(enums).Encode()
+
+	// build scalar name strategies
+	// 1. combine resource name and field name
+	var firstName string
+	var scalarName string
+	apiPath = strings.TrimPrefix(apiPath, "/")
+	if apiPath != "" {
+		apiPaths := strings.Split(apiPath, "/")
+		resourceName := fieldPaths[0]
+		if len(apiPaths) > 0 {
+			resourceName = apiPaths[0]
+		}
+		enumName := "Enum"
+		if len(fieldPaths) > 1 {
+			enumName = fieldPaths[len(fieldPaths)-1]
+		}
+
+		scalarName = utils.StringSliceToPascalCase([]string{resourceName, enumName})
+		if canSetEnumToSchema(sm, scalarName, enums) {
+			sm.ScalarTypes[scalarName] = *scalarType
+			return scalarName
+		}
+		firstName = scalarName
+	}
+
+	// 2. if the scalar type exists, fallback to field paths
+	scalarName = utils.StringSliceToPascalCase(fieldPaths)
+	if firstName == "" {
+		firstName = scalarName
+	}
+	if canSetEnumToSchema(sm, scalarName, enums) {
+		sm.ScalarTypes[scalarName] = *scalarType
+		if firstName != scalarName {
+			report.addNameCollision("scalar type", firstName, scalarName, strings.Join(fieldPaths, "."))
+		}
+		return scalarName
+	}
+
+	// 3. Reuse above name with Enum suffix
+	scalarName = fmt.Sprintf("%sEnum", scalarName)
+	if _, ok := sm.ScalarTypes[scalarName]; !ok {
+		sm.ScalarTypes[scalarName] = *scalarType
+	}
+	report.addNameCollision("scalar type", firstName, scalarName, strings.Join(fieldPaths, "."))
+	return scalarName
+}
+
 func canSetEnumToSchema(sm *rest.NDCRestSchema, scalarName string, enums []string) bool {
 	existedScalar, ok := sm.ScalarTypes[scalarName]
 	if !ok {
@@ -195,34 +562,107 @@ func createSchemaFromOpenAPISchema(input *base.Schema, typeName string) *rest.Ty
 	}
 	if typeName != "" {
 		ps.Type = typeName
-	} else {
-		if len(input.Type) > 1 {
-			ps.Type = string(rest.ScalarJSON)
-		} else if len(input.Type) > 0 {
-			ps.Type = input.Type[0]
-		}
-		ps.Format = input.Format
+	} else if len(input.Type) > 1 {
+		ps.Type = string(rest.ScalarJSON)
+	} else if len(input.Type) > 0 {
+		ps.Type = input.Type[0]
 	}
+	ps.Format = input.Format
 	ps.Pattern = input.Pattern
 	ps.Maximum = input.Maximum
 	ps.Minimum = input.Minimum
+	ps.ExclusiveMaximum = getExclusiveBound(input.ExclusiveMaximum, input.Maximum)
+	ps.ExclusiveMinimum = getExclusiveBound(input.ExclusiveMinimum, input.Minimum)
+	ps.MultipleOf = input.MultipleOf
 	ps.MaxLength = input.MaxLength
 	ps.MinLength = input.MinLength
+	ps.MaxItems = input.MaxItems
+	ps.MinItems = input.MinItems
+	ps.UniqueItems = input.UniqueItems != nil && *input.UniqueItems
+	ps.MaxProperties = input.MaxProperties
+	ps.MinProperties = input.MinProperties
 	ps.Description = input.Description
+	if len(input.Type) == 1 {
+		ps.Enum = decodeEnumNodes(getSchemaEnum(input), input.Type[0])
+	}
+	ps.Const = decodeNodeValue(input.Const)
+	ps.Default = decodeNodeValue(input.Default)
+	ps.Example = decodeNodeValue(input.Example)
+	ps.Examples = getSchemaExamples(input)
 	ps.ReadOnly = input.ReadOnly != nil && *input.ReadOnly
 	ps.WriteOnly = input.WriteOnly != nil && *input.WriteOnly
 
 	return ps
 }
 
+// mergeSchemaKeywords returns a copy of the type schema with validation keywords of the wrapper schema
+func mergeSchemaKeywords(ty *rest.TypeSchema, wrapper *base.Schema) *rest.TypeSchema {
+	result := *ty
+	keywords := createSchemaFromOpenAPISchema(wrapper, "")
+	if keywords.Format != "" {
+		result.Format = keywords.Format
+	}
+	if keywords.Pattern != "" {
+		result.Pattern = keywords.Pattern
+	}
+	if keywords.Maximum != nil {
+		result.Maximum = keywords.Maximum
+	}
+	if keywords.Minimum != nil {
+		result.Minimum = keywords.Minimum
+	}
+	if keywords.ExclusiveMaximum != nil {
+		result.ExclusiveMaximum = keywords.ExclusiveMaximum
+	}
+	if keywords.ExclusiveMinimum != nil {
+		result.ExclusiveMinimum = keywords.ExclusiveMinimum
+	}
+	if keywords.MultipleOf != nil {
+		result.MultipleOf = keywords.MultipleOf
+	}
+	if keywords.MaxLength != nil {
+		result.MaxLength = keywords.MaxLength
+	}
+	if keywords.MinLength != nil {
+		result.MinLength = keywords.MinLength
+	}
+	if keywords.MaxItems != nil {
+		result.MaxItems = keywords.MaxItems
+	}
+	if keywords.MinItems != nil {
+		result.MinItems = keywords.MinItems
+	}
+	if keywords.MaxProperties != nil {
+		result.MaxProperties = keywords.MaxProperties
+	}
+	if keywords.MinProperties != nil {
+		result.MinProperties = keywords.MinProperties
+	}
+	result.UniqueItems = result.UniqueItems || keywords.UniqueItems
+	if keywords.Const != nil {
+		result.Const = keywords.Const
+	}
+	if keywords.Default != nil {
+		result.Default = keywords.Default
+	}
+	if keywords.Example != nil {
+		result.Example = keywords.Example
+	}
+
+	return &result
+}
+
 // getMethodAlias merge method alias map with default value
 func getMethodAlias(inputs ...map[string]string) map[string]string {
 	methodAlias := map[string]string{
-		"get":    "get",
-		"post":   "post",
-		"put":    "put",
-		"patch":  "patch",
-		"delete": "delete",
+		"get":     "get",
+		"post":    "post",
+		"put":     "put",
+		"patch":   "patch",
+		"delete":  "delete",
+		"head":    "head",
+		"options": "options",
+		"trace":   "trace",
 	}
 	for _, input := range inputs {
 		for k, alias := range input {
@@ -336,6 +776,11 @@ func evalSchemaProxiesSlice(schemaProxies []*base.SchemaProxy, location rest.Par
 		if sc == nil || (len(sc.Type) == 0 && len(sc.AllOf) == 0 && len(sc.AnyOf) == 0 && len(sc.OneOf) == 0) {
 			continue
 		}
+		// the null type of OpenAPI 3.1 schemas makes the union nullable, e.g. anyOf: [{type: string}, {type: "null"}]
+		if len(sc.Type) == 1 && sc.Type[0] == "null" {
+			nullable = true
+			continue
+		}
 
 		switch location {
 		case rest.InQuery:
@@ -365,6 +810,113 @@ func evalSchemaProxiesSlice(schemaProxies []*base.SchemaProxy, location rest.Par
 	return results, nil, nullable
 }
 
+// get the discriminator value of the variant schema. The value is looked up from the mapping,
+// then the name of the referenced schema, then the single enum or const value of the discriminator property in the inline schema
+func getDiscriminatorValue(discriminator *base.Discriminator, proxy *base.SchemaProxy) string {
+	if proxy == nil {
+		return ""
+	}
+	if ref := proxy.GetReference(); ref != "" {
+		// the implicit value is the name of the schema, or the definition of $defs
+		refName := ""
+		if getSchemaRefTypeNameV3(ref) != "" {
+			refName = ref[strings.LastIndex(ref, "/")+1:]
+		}
+		if discriminator.Mapping != nil {
+			for m := discriminator.Mapping.First(); m != nil; m = m.Next() {
+				if m.Value() == ref || m.Value() == refName {
+					return m.Key()
+				}
+			}
+		}
+		return refName
+	}
+
+	variant := proxy.Schema()
+	if variant == nil || variant.Properties == nil {
+		return ""
+	}
+	propProxy, ok := variant.Properties.Get(discriminator.PropertyName)
+	if !ok || propProxy == nil || propProxy.Schema() == nil {
+		return ""
+	}
+	prop := propProxy.Schema()
+	if prop.Const != nil {
+		return prop.Const.Value
+	}
+	if len(prop.Enum) == 1 && prop.Enum[0] != nil {
+		return prop.Enum[0].Value
+	}
+	return ""
+}
+
+// check if the schema is a map of typed values without fixed properties, e.g. map<string, Price>
+func isTypedMapSchema(typeSchema *base.Schema) bool {
+	if typeSchema.AdditionalProperties == nil || typeSchema.AdditionalProperties.A == nil ||
+		(typeSchema.Properties != nil && typeSchema.Properties.Len() > 0) ||
+		len(typeSchema.OneOf) > 0 || len(typeSchema.AnyOf) > 0 || len(typeSchema.AllOf) > 0 {
+		return false
+	}
+	valueProxy := typeSchema.AdditionalProperties.A
+	if valueProxy.IsReference() {
+		return true
+	}
+	valueSchema := valueProxy.Schema()
+	return valueSchema != nil && (len(valueSchema.Type) > 0 || len(valueSchema.AllOf) > 0)
+}
+
+// register the key-value entry object type of a typed map and return the array type of entries.
+// The mapEntries marker of the type schema tells the connector to convert between the map and entries
+func buildMapEntriesType(sm *rest.NDCRestSchema, usageCounter TypeUsageCounter, report *ConversionReport, typeSchema *base.Schema, valueType schema.TypeEncoder, valueSchema *rest.TypeSchema, fieldPaths []string, pointer string) (schema.TypeEncoder, *rest.TypeSchema) {
+	scalarName := string(rest.ScalarString)
+	if _, ok := sm.ScalarTypes[scalarName]; !ok {
+		sm.ScalarTypes[scalarName] = *defaultScalarTypes[rest.ScalarString]
+	}
+
+	entryName := utils.StringSliceToPascalCase(append(slices.Clone(fieldPaths), "Entry"))
+	entryObject := schema.ObjectType{
+		Fields: schema.ObjectTypeFields{
+			"key": schema.ObjectField{
+				Type: schema.NewNamedType(scalarName).Encode(),
+			},
+			"value": schema.ObjectField{
+				Type: valueType.Encode(),
+			},
+		},
+	}
+	if valueSchema.Description != "" {
+		description := valueSchema.Description
+		entryObject.Fields["value"] = schema.ObjectField{
+			Type:        valueType.Encode(),
+			Description: &description,
+		}
+	}
+	sm.ObjectTypes[entryName] = entryObject
+	addObjectFieldsUsage(usageCounter, entryObject)
+	valuePointer := buildJSONPointer(pointer, "additionalProperties")
+	report.SourceMap.setObjectType(entryName, newSourceLocation(pointer, getSchemaNode(typeSchema)), map[string]SourceLocation{
+		"value": newSourceLocation(valuePointer, getSchemaProxyNode(typeSchema.AdditionalProperties.A)),
+	})
+
+	typeResult := createSchemaFromOpenAPISchema(typeSchema, "array")
+	typeResult.MapEntries = true
+	typeResult.Items = &rest.TypeSchema{
+		Type: entryName,
+		Properties: map[string]rest.TypeSchema{
+			"key":   {Type: scalarName},
+			"value": *valueSchema,
+		},
+	}
+	return schema.NewArrayType(schema.NewNamedType(entryName)), typeResult
+}
+
+// add usage of field types of the object type
+func addObjectFieldsUsage(usageCounter TypeUsageCounter, object schema.ObjectType) {
+	for _, field := range object.Fields {
+		usageCounter.Add(getNamedType(field.Type.Interface(), true, ""), 1)
+	}
+}
+
 func cleanUnusedSchemaTypes(schema *rest.NDCRestSchema, usageCounter *TypeUsageCounter) {
 	for key := range schema.ObjectTypes {
 		cleanUnusedObjectType(schema, usageCounter, key)
@@ -374,6 +926,71 @@ func cleanUnusedSchemaTypes(schema *rest.NDCRestSchema, usageCounter *TypeUsageC
 			delete(schema.ScalarTypes, key)
 		}
 	}
+	cleanUnreachableSchemaTypes(schema)
+}
+
+// cleanUnreachableSchemaTypes removes types that can't be reached from arguments and results of operations.
+// The usage counter may still count references from types that were never added to the schema, e.g. inline types of skipped operations
+func cleanUnreachableSchemaTypes(sm *rest.NDCRestSchema) {
+	reachable := map[string]bool{}
+	var visit func(schemaType schema.Type)
+	visit = func(schemaType schema.Type) {
+		if schemaType == nil {
+			return
+		}
+		name := getNamedType(schemaType.Interface(), true, "")
+		if name == "" || reachable[name] {
+			return
+		}
+		reachable[name] = true
+		if object, ok := sm.ObjectTypes[name]; ok {
+			for _, field := range object.Fields {
+				visit(field.Type)
+			}
+		}
+	}
+
+	// error payloads of documented status codes are returned as structured errors
+	visitResponse := func(request *rest.Request) {
+		if request == nil {
+			return
+		}
+		for _, content := range request.Response.StatusCodes {
+			visit(content.Type)
+		}
+	}
+	for _, collection := range sm.Collections {
+		for _, arg := range collection.Arguments {
+			visit(arg.Type)
+		}
+		visit(schema.NewNamedType(collection.Type).Encode())
+		visitResponse(collection.Request)
+	}
+	for _, fn := range sm.Functions {
+		for _, arg := range fn.Arguments {
+			visit(arg.Type)
+		}
+		visit(fn.ResultType)
+		visitResponse(fn.Request)
+	}
+	for _, proc := range sm.Procedures {
+		for _, arg := range proc.Arguments {
+			visit(arg.Type)
+		}
+		visit(proc.ResultType)
+		visitResponse(proc.Request)
+	}
+
+	for key := range sm.ObjectTypes {
+		if !reachable[key] {
+			delete(sm.ObjectTypes, key)
+		}
+	}
+	for key := range sm.ScalarTypes {
+		if !reachable[key] {
+			delete(sm.ScalarTypes, key)
+		}
+	}
 }
 
 // recursively clean unused objects as well as their inner properties
@@ -402,11 +1019,115 @@ func formatWriteObjectName(name string) string {
 	return fmt.Sprintf("%sInput", name)
 }
 
-func errParameterSchemaEmpty(fieldPaths []string) error {
-	return fmt.Errorf("parameter schema of $.%s is empty", strings.Join(fieldPaths, "."))
+// build the envelope object type that wraps the response headers and body
+func buildResponseEnvelope(sm *rest.NDCRestSchema, usageCounter TypeUsageCounter, bodyType schema.TypeEncoder, headersType schema.TypeEncoder, fieldPaths []string) schema.TypeEncoder {
+	objectName := utils.StringSliceToPascalCase(append(slices.Clone(fieldPaths), "Envelope"))
+	sm.ObjectTypes[objectName] = schema.ObjectType{
+		Fields: schema.ObjectTypeFields{
+			rest.ResponseEnvelopeHeadersField: {
+				Type: headersType.Encode(),
+			},
+			rest.ResponseEnvelopeBodyField: {
+				Type: bodyType.Encode(),
+			},
+		},
+	}
+	usageCounter.Add(objectName, 1)
+	return schema.NewNamedType(objectName)
+}
+
+// responseUnionMember represents the body type of a success response in the union result
+type responseUnionMember struct {
+	StatusCode string
+	Type       schema.TypeEncoder
+}
+
+// build the union object type of success responses if they have different types.
+// The object has the status code field and a nullable field for the body of each success response.
+// Returns nil if all success responses have the same type
+func buildResponseUnion(sm *rest.NDCRestSchema, usageCounter TypeUsageCounter, members []responseUnionMember, hasBodyless bool, statusCodes map[string]rest.ResponseContent, fieldPaths []string) schema.TypeEncoder {
+	if len(members) == 0 {
+		return nil
+	}
+	sameType := !hasBodyless
+	for _, member := range members[1:] {
+		if !reflect.DeepEqual(member.Type.Encode(), members[0].Type.Encode()) {
+			sameType = false
+			break
+		}
+	}
+	if sameType {
+		return nil
+	}
+
+	scalarName := string(rest.ScalarInt32)
+	if _, ok := sm.ScalarTypes[scalarName]; !ok {
+		sm.ScalarTypes[scalarName] = *defaultScalarTypes[rest.ScalarInt32]
+	}
+	usageCounter.Add(scalarName, 1)
+	object := schema.ObjectType{
+		Fields: schema.ObjectTypeFields{
+			rest.ResponseUnionStatusCodeField: {
+				Type: schema.NewNamedType(scalarName).Encode(),
+			},
+		},
+	}
+	for _, member := range members {
+		fieldName := "status" + member.StatusCode
+		memberType := member.Type
+		if !isNullableType(memberType) {
+			memberType = schema.NewNullableType(memberType)
+		}
+		object.Fields[fieldName] = schema.ObjectField{
+			Type: memberType.Encode(),
+		}
+		content := statusCodes[member.StatusCode]
+		content.FieldName = fieldName
+		statusCodes[member.StatusCode] = content
+	}
+
+	objectName := utils.StringSliceToPascalCase(append(slices.Clone(fieldPaths), "Union"))
+	sm.ObjectTypes[objectName] = object
+	usageCounter.Add(objectName, 1)
+	return schema.NewNamedType(objectName)
+}
+
+// check if the response status code is a success code, e.g. 200 or 2XX
+func isSuccessStatusCode(code string) bool {
+	return len(code) == 3 && code[0] == '2'
+}
+
+// check if the response status code is an error code, e.g. 404, 4XX, 5XX or default
+func isErrorStatusCode(code string) bool {
+	return code == "default" || (len(code) == 3 && (code[0] == '4' || code[0] == '5'))
+}
+
+func errParameterSchemaEmpty(fieldPaths []string, pointer string) error {
+	return fmt.Errorf("parameter schema of $.%s is empty at %s", strings.Join(fieldPaths, "."), pointer)
+}
+
+func errArrayItemEmpty(fieldPaths []string, pointer string) error {
+	return fmt.Errorf("array item of $.%s is empty at %s", strings.Join(fieldPaths, "."), buildJSONPointer(pointer, "items"))
+}
+
+func errUnsupportedSchemaType(typeName string, pointer string) error {
+	return fmt.Errorf("unsupported schema type %s at %s", typeName, pointer)
 }
 
 // redirection and information response status codes aren't supported
 func isUnsupportedResponseCodes[T int | int64](code T) bool {
 	return code < 200 || (code >= 300 && code < 400)
 }
+
+func getSortedKeys[V any](input map[string]V) []string {
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func toPtr[V any](value V) *V {
+	return &value
+}
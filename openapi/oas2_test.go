@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"slices"
 	"testing"
 
-	"github.com/hasura/ndc-rest-schema/schema"
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
 )
 
 func TestOpenAPIv2ToRESTSchema(t *testing.T) {
@@ -50,16 +53,132 @@ func TestOpenAPIv2ToRESTSchema(t *testing.T) {
 				Prefix: "hasura_mock_json",
 			},
 		},
+		// go run . convert -c ./openapi/testdata/operation_kinds/config-swagger.yaml
+		{
+			Name:     "operation_kinds",
+			Source:   "testdata/operation_kinds/swagger.yaml",
+			Expected: "testdata/operation_kinds/expected-swagger.json",
+			Options: ConvertOptions{
+				OperationKinds: []utils.OperationKindRule{
+					{OperationFilter: utils.OperationFilter{Paths: []string{"/pets/*/refresh"}}, Kind: rest.OperationProcedure},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/extra_methods/swagger.yaml -o ./openapi/testdata/extra_methods/expected-swagger.json --spec oas2 --extra-methods head,options
+		{
+			Name:     "extra_methods",
+			Source:   "testdata/extra_methods/swagger.yaml",
+			Expected: "testdata/extra_methods/expected-swagger.json",
+			Options: ConvertOptions{
+				ExtraMethods: []string{"head", "options"},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/response_headers/swagger.yaml -o ./openapi/testdata/response_headers/expected-swagger.json --spec oas2 --response-headers
+		{
+			Name:     "response_headers",
+			Source:   "testdata/response_headers/swagger.yaml",
+			Expected: "testdata/response_headers/expected-swagger.json",
+			Options: ConvertOptions{
+				ResponseHeaders: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/status_codes/swagger.yaml -o ./openapi/testdata/status_codes/expected-swagger.json --spec oas2 --status-code-responses
+		{
+			Name:     "status_codes",
+			Source:   "testdata/status_codes/swagger.yaml",
+			Expected: "testdata/status_codes/expected-swagger.json",
+			Options: ConvertOptions{
+				StatusCodeResponses: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/formats/swagger.yaml -o ./openapi/testdata/formats/expected-swagger.json --spec oas2
+		{
+			Name:     "formats",
+			Source:   "testdata/formats/swagger.yaml",
+			Expected: "testdata/formats/expected-swagger.json",
+		},
+		// go run . convert -c ./openapi/testdata/scalar_mappings/config-swagger.yaml
+		{
+			Name:     "scalar_mappings",
+			Source:   "testdata/scalar_mappings/swagger.yaml",
+			Expected: "testdata/scalar_mappings/expected-swagger.json",
+			Options: ConvertOptions{
+				ScalarMappings: []utils.ScalarMapping{
+					{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeBigDecimal},
+					{Type: "string", Extension: "x-scalar-type", ExtensionValue: "snowflake", Scalar: "SnowflakeID"},
+					{Type: "string", Format: "date-time", Scalar: "DateTime", Representation: schema.TypeRepresentationTypeTimestampTZ},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/swagger.yaml -o ./openapi/testdata/number_representation/expected-swagger.json --spec oas2
+		{
+			Name:     "number_representation",
+			Source:   "testdata/number_representation/swagger.yaml",
+			Expected: "testdata/number_representation/expected-swagger.json",
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/swagger.yaml -o ./openapi/testdata/number_representation/expected-swagger-string.json --spec oas2 --int-64-representation string --decimal-representation string
+		{
+			Name:     "number_representation_string",
+			Source:   "testdata/number_representation/swagger.yaml",
+			Expected: "testdata/number_representation/expected-swagger-string.json",
+			Options: ConvertOptions{
+				Int64Representation:   rest.NumberRepresentationString,
+				DecimalRepresentation: rest.NumberRepresentationString,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/swagger.yaml -o ./openapi/testdata/number_representation/expected-swagger-bigdecimal.json --spec oas2 --int-64-representation number --decimal-representation bigdecimal
+		{
+			Name:     "number_representation_bigdecimal",
+			Source:   "testdata/number_representation/swagger.yaml",
+			Expected: "testdata/number_representation/expected-swagger-bigdecimal.json",
+			Options: ConvertOptions{
+				Int64Representation:   rest.NumberRepresentationNumber,
+				DecimalRepresentation: rest.NumberRepresentationBigDecimal,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/numeric_enums/swagger.yaml -o ./openapi/testdata/numeric_enums/expected-swagger.json --spec oas2
+		{
+			Name:     "numeric_enums",
+			Source:   "testdata/numeric_enums/swagger.yaml",
+			Expected: "testdata/numeric_enums/expected-swagger.json",
+		},
+		// go run . convert -f ./openapi/testdata/typed_maps/swagger.yaml -o ./openapi/testdata/typed_maps/expected-swagger.json --spec oas2 --typed-maps
+		{
+			Name:     "typed_maps",
+			Source:   "testdata/typed_maps/swagger.yaml",
+			Expected: "testdata/typed_maps/expected-swagger.json",
+			Options: ConvertOptions{
+				TypedMaps: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/keywords/swagger.yaml -o ./openapi/testdata/keywords/expected-swagger.json --spec oas2
+		{
+			Name:     "keywords",
+			Source:   "testdata/keywords/swagger.yaml",
+			Expected: "testdata/keywords/expected-swagger.json",
+		},
+		// go run . convert -f ./openapi/testdata/collections/swagger.yaml -o ./openapi/testdata/collections/expected-swagger.json --spec oas2 --detect-collections
+		{
+			Name:     "collections",
+			Source:   "testdata/collections/swagger.yaml",
+			Expected: "testdata/collections/expected-swagger.json",
+			Options: ConvertOptions{
+				DetectCollections: true,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 			sourceBytes, err := os.ReadFile(tc.Source)
 			assertNoError(t, err)
+			// the convert command reads YAML documents as JSON in the same way
+			sourceBytes, err = utils.ApplyPatch(sourceBytes, nil)
+			assertNoError(t, err)
 
 			expectedBytes, err := os.ReadFile(tc.Expected)
 			assertNoError(t, err)
-			var expected schema.NDCRestSchema
+			var expected rest.NDCRestSchema
 			assertNoError(t, json.Unmarshal(expectedBytes, &expected))
 
 			output, errs := OpenAPIv2ToNDCSchema(sourceBytes, tc.Options)
@@ -72,6 +191,71 @@ func TestOpenAPIv2ToRESTSchema(t *testing.T) {
 		})
 	}
 
+	t.Run("report", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore2/swagger.json")
+		assertNoError(t, err)
+
+		output, report, errs := OpenAPIv2ToNDCSchemaWithReport(sourceBytes, ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		expectedSkipped := SkippedOperation{
+			Path:        "/user/logout-redirect",
+			Method:      "get",
+			OperationID: "logoutUserRedirect",
+			Pointer:     "/paths/~1user~1logout-redirect/get",
+			Reason:      "unsupported response status code 302",
+		}
+		if !slices.Contains(report.SkippedOperations, expectedSkipped) {
+			t.Errorf("expected skipped operation %+v, got: %+v", expectedSkipped, report.SkippedOperations)
+		}
+		expectedFallback := JSONFallback{
+			Name:    "getInventory.Result",
+			Pointer: "/paths/~1store~1inventory/get/responses/200/schema",
+			Reason:  "object with additionalProperties",
+		}
+		if !slices.Contains(report.JSONFallbacks, expectedFallback) {
+			t.Errorf("expected JSON fallback %+v, got: %+v", expectedFallback, report.JSONFallbacks)
+		}
+	})
+
+	failureCases := []struct {
+		Name     string
+		Source   string
+		Options  ConvertOptions
+		Expected string
+	}{
+		{
+			Name:   "failure_extra_method",
+			Source: "testdata/extra_methods/swagger.yaml",
+			Options: ConvertOptions{
+				ExtraMethods: []string{"connect"},
+			},
+			Expected: "invalid extra method connect",
+		},
+		{
+			Name:   "failure_operation_kind_rule",
+			Source: "testdata/operation_kinds/swagger.yaml",
+			Options: ConvertOptions{
+				OperationKinds: []utils.OperationKindRule{
+					{OperationFilter: utils.OperationFilter{Methods: []string{"post"}}, Kind: "query"},
+				},
+			},
+			Expected: "operationKinds[0]",
+		},
+	}
+
+	for _, tc := range failureCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assertNoError(t, err)
+			_, errs := OpenAPIv2ToNDCSchema(sourceBytes, tc.Options)
+			assertDeepEqual(t, 1, len(errs))
+			assertError(t, errs[0], tc.Expected)
+		})
+	}
+
 	t.Run("failure_empty", func(t *testing.T) {
 		_, err := OpenAPIv2ToNDCSchema([]byte(""), ConvertOptions{})
 		assertError(t, errors.Join(err...), "there is nothing in the spec, it's empty")
@@ -10,28 +10,36 @@ import (
 
 type ConvertOptions internal.ConvertOptions
 
-// OpenAPIv3ToNDCSchema converts OpenAPI v3 JSON bytes to NDC REST schema
+// OpenAPIv3ToNDCSchema converts OpenAPI v3 JSON bytes to NDC REST schema.
+// In tolerant mode, operations that fail to be converted are skipped and their errors are returned with the schema
 func OpenAPIv3ToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCRestSchema, []error) {
+	result, _, errs := OpenAPIv3ToNDCSchemaWithReport(input, options)
+	return result, errs
+}
+
+// OpenAPIv3ToNDCSchemaWithReport converts OpenAPI v3 JSON bytes to NDC REST schema.
+// The report lists skipped operations, schemas that fell back to the JSON scalar and resolved name collisions
+func OpenAPIv3ToNDCSchemaWithReport(input []byte, options ConvertOptions) (*rest.NDCRestSchema, *ConversionReport, []error) {
 
 	document, err := libopenapi.NewDocument(input)
 	if err != nil {
-		return nil, []error{err}
+		return nil, nil, []error{err}
 	}
 
 	docModel, errs := document.BuildV3Model()
 	// The errors won’t prevent the model from building
 	if docModel == nil && len(errs) > 0 {
-		return nil, errs
+		return nil, nil, errs
 	}
 
 	if docModel.Model.Paths == nil || docModel.Model.Paths.PathItems == nil || docModel.Model.Paths.PathItems.IsZero() {
-		return nil, append(errs, errors.New("there is no API to be converted"))
+		return nil, nil, append(errs, errors.New("there is no API to be converted"))
 	}
 
 	converter := internal.NewOAS3Builder(rest.NewNDCRestSchema(), internal.ConvertOptions(options))
 	if err := converter.BuildDocumentModel(docModel); err != nil {
-		return nil, append(errs, err)
+		return nil, nil, append(errs, err)
 	}
 
-	return converter.Schema(), nil
+	return converter.Schema(), converter.Report(), converter.OperationErrors()
 }
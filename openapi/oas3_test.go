@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
-	"github.com/hasura/ndc-rest-schema/schema"
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-rest-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
 )
 
 func TestOpenAPIv3ToRESTSchema(t *testing.T) {
@@ -30,6 +33,31 @@ func TestOpenAPIv3ToRESTSchema(t *testing.T) {
 				EnvPrefix:  "PET_STORE",
 			},
 		},
+		// go run . convert -c ./openapi/testdata/petstore3/config-filter.yaml
+		{
+			Name:     "petstore3_filter",
+			Source:   "testdata/petstore3/source.json",
+			Expected: "testdata/petstore3/expected-filter.json",
+			Options: ConvertOptions{
+				TrimPrefix: "/v1",
+				EnvPrefix:  "PET_STORE",
+				Include: []utils.OperationFilter{
+					{Tags: []string{"pet", "store"}},
+					{Paths: []string{"/snake"}, Methods: []string{"get"}},
+				},
+				Exclude: []utils.OperationFilter{
+					{Methods: []string{"delete"}},
+					{OperationID: "^upload"},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/petstore31/source.json -o ./openapi/testdata/petstore31/expected.json --spec openapi3
+		{
+			Name:     "petstore31",
+			Source:   "testdata/petstore31/source.json",
+			Expected: "testdata/petstore31/expected.json",
+			Options:  ConvertOptions{},
+		},
 		// go run . convert -f ./openapi/testdata/onesignal/source.json -o ./openapi/testdata/onesignal/expected.json --spec openapi3
 		{
 			Name:     "onesignal",
@@ -62,16 +90,191 @@ func TestOpenAPIv3ToRESTSchema(t *testing.T) {
 				Prefix: "hasura_one_signal",
 			},
 		},
+		// go run . convert -c ./openapi/testdata/operation_kinds/config-openapi3.yaml
+		{
+			Name:     "operation_kinds",
+			Source:   "testdata/operation_kinds/openapi3.yaml",
+			Expected: "testdata/operation_kinds/expected-openapi3.json",
+			Options: ConvertOptions{
+				Tolerant: true,
+				OperationKinds: []utils.OperationKindRule{
+					{OperationFilter: utils.OperationFilter{Paths: []string{"/pets/query"}}, Kind: rest.OperationFunction},
+					// config rules take precedence over the x-ndc-operation: procedure extension
+					{OperationFilter: utils.OperationFilter{OperationID: "^refreshPet$"}, Kind: rest.OperationFunction},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/extra_methods/openapi3.yaml -o ./openapi/testdata/extra_methods/expected-openapi3.json --spec openapi3 --extra-methods head,options,trace
+		{
+			Name:     "extra_methods",
+			Source:   "testdata/extra_methods/openapi3.yaml",
+			Expected: "testdata/extra_methods/expected-openapi3.json",
+			Options: ConvertOptions{
+				ExtraMethods: []string{"HEAD", "options", "trace"},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/extra_methods/openapi3.yaml -o ./openapi/testdata/extra_methods/expected-openapi3-disabled.json --spec openapi3
+		{
+			Name:     "extra_methods_disabled",
+			Source:   "testdata/extra_methods/openapi3.yaml",
+			Expected: "testdata/extra_methods/expected-openapi3-disabled.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/response_headers/openapi3.yaml -o ./openapi/testdata/response_headers/expected-openapi3.json --spec openapi3 --response-headers
+		{
+			Name:     "response_headers",
+			Source:   "testdata/response_headers/openapi3.yaml",
+			Expected: "testdata/response_headers/expected-openapi3.json",
+			Options: ConvertOptions{
+				ResponseHeaders: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/response_headers/openapi3.yaml -o ./openapi/testdata/response_headers/expected-openapi3-disabled.json --spec openapi3
+		{
+			Name:     "response_headers_disabled",
+			Source:   "testdata/response_headers/openapi3.yaml",
+			Expected: "testdata/response_headers/expected-openapi3-disabled.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/status_codes/openapi3.yaml -o ./openapi/testdata/status_codes/expected-openapi3.json --spec openapi3 --status-code-responses
+		{
+			Name:     "status_codes",
+			Source:   "testdata/status_codes/openapi3.yaml",
+			Expected: "testdata/status_codes/expected-openapi3.json",
+			Options: ConvertOptions{
+				StatusCodeResponses: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/status_codes/openapi3.yaml -o ./openapi/testdata/status_codes/expected-openapi3-disabled.json --spec openapi3
+		{
+			Name:     "status_codes_disabled",
+			Source:   "testdata/status_codes/openapi3.yaml",
+			Expected: "testdata/status_codes/expected-openapi3-disabled.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/discriminator/openapi3.yaml -o ./openapi/testdata/discriminator/expected-openapi3.json --spec openapi3
+		{
+			Name:     "discriminator",
+			Source:   "testdata/discriminator/openapi3.yaml",
+			Expected: "testdata/discriminator/expected-openapi3.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/formats/openapi3.yaml -o ./openapi/testdata/formats/expected-openapi3.json --spec openapi3
+		{
+			Name:     "formats",
+			Source:   "testdata/formats/openapi3.yaml",
+			Expected: "testdata/formats/expected-openapi3.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -c ./openapi/testdata/scalar_mappings/config-openapi3.yaml
+		{
+			Name:     "scalar_mappings",
+			Source:   "testdata/scalar_mappings/openapi3.yaml",
+			Expected: "testdata/scalar_mappings/expected-openapi3.json",
+			Options: ConvertOptions{
+				ScalarMappings: []utils.ScalarMapping{
+					{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeBigDecimal},
+					{Type: "string", Extension: "x-scalar-type", ExtensionValue: "snowflake", Scalar: "SnowflakeID"},
+					{Type: "string", Format: "date-time", Scalar: "DateTime", Representation: schema.TypeRepresentationTypeTimestampTZ},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/openapi3.yaml -o ./openapi/testdata/number_representation/expected-openapi3.json --spec openapi3
+		{
+			Name:     "number_representation",
+			Source:   "testdata/number_representation/openapi3.yaml",
+			Expected: "testdata/number_representation/expected-openapi3.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/openapi3.yaml -o ./openapi/testdata/number_representation/expected-openapi3-string.json --spec openapi3 --int-64-representation string --decimal-representation string
+		{
+			Name:     "number_representation_string",
+			Source:   "testdata/number_representation/openapi3.yaml",
+			Expected: "testdata/number_representation/expected-openapi3-string.json",
+			Options: ConvertOptions{
+				Int64Representation:   rest.NumberRepresentationString,
+				DecimalRepresentation: rest.NumberRepresentationString,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/number_representation/openapi3.yaml -o ./openapi/testdata/number_representation/expected-openapi3-bigdecimal.json --spec openapi3 --int-64-representation number --decimal-representation bigdecimal
+		{
+			Name:     "number_representation_bigdecimal",
+			Source:   "testdata/number_representation/openapi3.yaml",
+			Expected: "testdata/number_representation/expected-openapi3-bigdecimal.json",
+			Options: ConvertOptions{
+				Int64Representation:   rest.NumberRepresentationNumber,
+				DecimalRepresentation: rest.NumberRepresentationBigDecimal,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/numeric_enums/openapi3.yaml -o ./openapi/testdata/numeric_enums/expected-openapi3.json --spec openapi3
+		{
+			Name:     "numeric_enums",
+			Source:   "testdata/numeric_enums/openapi3.yaml",
+			Expected: "testdata/numeric_enums/expected-openapi3.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/typed_maps/openapi3.yaml -o ./openapi/testdata/typed_maps/expected-openapi3.json --spec openapi3 --typed-maps
+		{
+			Name:     "typed_maps",
+			Source:   "testdata/typed_maps/openapi3.yaml",
+			Expected: "testdata/typed_maps/expected-openapi3.json",
+			Options: ConvertOptions{
+				TypedMaps: true,
+			},
+		},
+		// go run . convert -f ./openapi/testdata/typed_maps/openapi3.yaml -o ./openapi/testdata/typed_maps/expected-openapi3-disabled.json --spec openapi3
+		{
+			Name:     "typed_maps_disabled",
+			Source:   "testdata/typed_maps/openapi3.yaml",
+			Expected: "testdata/typed_maps/expected-openapi3-disabled.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/keywords/openapi3.yaml -o ./openapi/testdata/keywords/expected-openapi3.json --spec openapi3
+		{
+			Name:     "keywords",
+			Source:   "testdata/keywords/openapi3.yaml",
+			Expected: "testdata/keywords/expected-openapi3.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -f ./openapi/testdata/keywords/openapi31.yaml -o ./openapi/testdata/keywords/expected-openapi31.json --spec openapi3
+		{
+			Name:     "keywords31",
+			Source:   "testdata/keywords/openapi31.yaml",
+			Expected: "testdata/keywords/expected-openapi31.json",
+			Options:  ConvertOptions{},
+		},
+		// go run . convert -c ./openapi/testdata/collections/config-openapi3.yaml
+		{
+			Name:     "collections",
+			Source:   "testdata/collections/openapi3.yaml",
+			Expected: "testdata/collections/expected-openapi3.json",
+			Options: ConvertOptions{
+				DetectCollections: true,
+				Collections: []utils.CollectionRule{
+					{OperationFilter: utils.OperationFilter{OperationID: "^listOwners$"}},
+				},
+			},
+		},
+		// go run . convert -f ./openapi/testdata/collections/openapi3.yaml -o ./openapi/testdata/collections/expected-openapi3-disabled.json --spec openapi3
+		{
+			Name:     "collections_disabled",
+			Source:   "testdata/collections/openapi3.yaml",
+			Expected: "testdata/collections/expected-openapi3-disabled.json",
+			Options:  ConvertOptions{},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 			sourceBytes, err := os.ReadFile(tc.Source)
 			assertNoError(t, err)
+			// the convert command reads YAML documents as JSON in the same way
+			sourceBytes, err = utils.ApplyPatch(sourceBytes, nil)
+			assertNoError(t, err)
 
 			expectedBytes, err := os.ReadFile(tc.Expected)
 			assertNoError(t, err)
-			var expected schema.NDCRestSchema
+			var expected rest.NDCRestSchema
 			assertNoError(t, json.Unmarshal(expectedBytes, &expected))
 
 			output, errs := OpenAPIv3ToNDCSchema(sourceBytes, tc.Options)
@@ -83,6 +286,148 @@ func TestOpenAPIv3ToRESTSchema(t *testing.T) {
 		})
 	}
 
+	t.Run("webhooks", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore31/source.json")
+		assertNoError(t, err)
+		_, report, errs := OpenAPIv3ToNDCSchemaWithReport(sourceBytes, ConvertOptions{})
+		if len(errs) > 0 {
+			t.Fatal(errors.Join(errs...))
+		}
+		assertDeepEqual(t, []SkippedOperation{
+			{Path: "newPet", Method: "post", OperationID: "newPetWebhook", Pointer: "/webhooks/newPet/post", Reason: "webhooks are not supported"},
+		}, report.SkippedOperations)
+		assertDeepEqual(t, []JSONFallback{}, report.JSONFallbacks)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore3/source.json")
+		assertNoError(t, err)
+
+		output, report, errs := OpenAPIv3ToNDCSchemaWithReport(sourceBytes, ConvertOptions{
+			TrimPrefix: "/v1",
+			EnvPrefix:  "PET_STORE",
+			Exclude: []utils.OperationFilter{
+				{OperationID: "^deletePet$"},
+			},
+		})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		for _, expected := range []SkippedOperation{
+			{Path: "/pet/{petId}", Method: "delete", OperationID: "deletePet", Pointer: "/paths/~1pet~1{petId}/delete", Reason: "excluded by filter rules"},
+			{Path: "/user", Method: "post", OperationID: "createUser", Pointer: "/paths/~1user/post", Reason: "no response status code"},
+			{Path: "/self-service/browser/flows/logout", Method: "get", OperationID: "initializeSelfServiceBrowserLogoutFlow", Pointer: "/paths/~1self-service~1browser~1flows~1logout/get", Reason: "unsupported response status code 302"},
+		} {
+			if !slices.Contains(report.SkippedOperations, expected) {
+				t.Errorf("expected skipped operation %+v, got: %+v", expected, report.SkippedOperations)
+			}
+		}
+
+		for _, expected := range []JSONFallback{
+			{Name: "getInventory.Result", Pointer: "/paths/~1store~1inventory/get/responses/200/content/application~1json/schema", Reason: "object with additionalProperties"},
+			{Name: "treasury.inbound_transfer.transaction", Pointer: "/components/schemas/treasury.inbound_transfer/properties/transaction/anyOf", Reason: "anyOf with non-object schemas"},
+		} {
+			if !slices.Contains(report.JSONFallbacks, expected) {
+				t.Errorf("expected JSON fallback %+v, got: %+v", expected, report.JSONFallbacks)
+			}
+		}
+
+		expectedCollision := NameCollision{
+			Kind:         "scalar type",
+			Name:         "CheckoutType",
+			ResolvedName: "PostCheckoutSessionsBodyCustomFieldsType",
+			Source:       "PostCheckoutSessions.Body.custom_fields.type",
+		}
+		if !slices.Contains(report.NameCollisions, expectedCollision) {
+			t.Errorf("expected name collision %+v, got: %+v", expectedCollision, report.NameCollisions)
+		}
+
+		summary := report.String()
+		for _, expected := range []string{"Skipped operations (", "JSON fallbacks (", "Name collisions (", "initializeSelfServiceBrowserLogoutFlow"} {
+			if !strings.Contains(summary, expected) {
+				t.Errorf("expected the summary contains %s, got: %s", expected, summary)
+			}
+		}
+		assertDeepEqual(t, "No skipped operation, JSON fallback or name collision.\n", ConversionReport{}.String())
+	})
+
+	t.Run("json_fallbacks", func(t *testing.T) {
+		fallbackCases := []struct {
+			Source   string
+			Options  ConvertOptions
+			Expected JSONFallback
+		}{
+			// fall back to JSON if the discriminator value of any variant can't be resolved
+			{
+				Source:   "testdata/discriminator/openapi3.yaml",
+				Expected: JSONFallback{Name: "getTag.Result", Pointer: "/paths/~1tags/get/responses/200/content/application~1json/schema/oneOf/0", Reason: "discriminator value of the variant can't be resolved"},
+			},
+			// maps are JSON scalars if typed maps are disabled
+			{
+				Source:   "testdata/typed_maps/openapi3.yaml",
+				Expected: JSONFallback{Name: "Product.names", Pointer: "/components/schemas/Product/properties/names", Reason: "object with additionalProperties"},
+			},
+		}
+
+		for _, tc := range fallbackCases {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assertNoError(t, err)
+			output, report, errs := OpenAPIv3ToNDCSchemaWithReport(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+			if !slices.Contains(report.JSONFallbacks, tc.Expected) {
+				t.Errorf("%s: expected JSON fallback %+v, got: %+v", tc.Source, tc.Expected, report.JSONFallbacks)
+			}
+		}
+	})
+
+	failureCases := []struct {
+		Name     string
+		Source   string
+		Options  ConvertOptions
+		Expected string
+	}{
+		{
+			Name:     "failure_operation_kind_extension",
+			Source:   "testdata/operation_kinds/openapi3.yaml",
+			Options:  ConvertOptions{Tolerant: true},
+			Expected: "x-ndc-operation: invalid OperationKind",
+		},
+		{
+			Name:     "failure_collection_extension",
+			Source:   "testdata/collections/invalid.yaml",
+			Expected: "x-ndc-collection: cannot convert function listTags to a collection: items of the list aren't objects",
+		},
+		{
+			Name:   "failure_scalar_mapping",
+			Source: "testdata/scalar_mappings/openapi3.yaml",
+			Options: ConvertOptions{
+				ScalarMappings: []utils.ScalarMapping{{Format: "money"}},
+			},
+			Expected: "scalarMappings[0]: scalar name is required",
+		},
+		{
+			Name:   "failure_number_representation",
+			Source: "testdata/number_representation/openapi3.yaml",
+			Options: ConvertOptions{
+				Int64Representation: "int64",
+			},
+			Expected: "int64Representation: invalid NumberRepresentation",
+		},
+	}
+
+	for _, tc := range failureCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assertNoError(t, err)
+			_, errs := OpenAPIv3ToNDCSchema(sourceBytes, tc.Options)
+			assertDeepEqual(t, 1, len(errs))
+			assertError(t, errs[0], tc.Expected)
+		})
+	}
+
 	t.Run("failure_empty", func(t *testing.T) {
 		_, err := OpenAPIv3ToNDCSchema([]byte(""), ConvertOptions{})
 		assertError(t, errors.Join(err...), "there is nothing in the spec, it's empty")
@@ -124,7 +469,16 @@ func assertDeepEqual(t *testing.T, expected any, reality any, msgs ...string) {
 	}
 }
 
-func assertRESTSchemaEqual(t *testing.T, expected *schema.NDCRestSchema, output *schema.NDCRestSchema) {
+func getSortedMapKeys[V any](input map[string]V) []string {
+	results := make([]string, 0, len(input))
+	for key := range input {
+		results = append(results, key)
+	}
+	slices.Sort(results)
+	return results
+}
+
+func assertRESTSchemaEqual(t *testing.T, expected *rest.NDCRestSchema, output *rest.NDCRestSchema) {
 	assertDeepEqual(t, expected.Collections, output.Collections, "Collections")
 	assertDeepEqual(t, expected.Settings, output.Settings, "Settings")
 	assertDeepEqual(t, len(expected.ScalarTypes), len(output.ScalarTypes), "ScalarTypes")
@@ -0,0 +1,28 @@
+package openapi
+
+import "github.com/hasura/ndc-rest-schema/openapi/internal"
+
+// ConversionReport represents the structured report of skipped operations,
+// schemas that fell back to the JSON scalar and resolved name collisions during the conversion
+type ConversionReport = internal.ConversionReport
+
+// SkippedOperation represents an API operation that isn't converted
+type SkippedOperation = internal.SkippedOperation
+
+// JSONFallback represents a schema that is converted to the arbitrary JSON scalar
+type JSONFallback = internal.JSONFallback
+
+// NameCollision represents a name collision that was resolved by renaming
+type NameCollision = internal.NameCollision
+
+// OperationError represents an error of an API operation that is skipped in tolerant mode
+type OperationError = internal.OperationError
+
+// SourceMap maps generated functions, procedures, object types, fields and enum scalars to their locations in the source document
+type SourceMap = internal.SourceMap
+
+// SourceLocation represents the location of an element in the source document
+type SourceLocation = internal.SourceLocation
+
+// ObjectTypeSource represents the location of an object type and its fields in the source document
+type ObjectTypeSource = internal.ObjectTypeSource
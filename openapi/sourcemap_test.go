@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestSourceMap(t *testing.T) {
+	t.Run("petstore3", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore3/source.json")
+		assertNoError(t, err)
+
+		output, report, errs := OpenAPIv3ToNDCSchemaWithReport(sourceBytes, ConvertOptions{
+			TrimPrefix: "/v1",
+			EnvPrefix:  "PET_STORE",
+			SourceMap:  true,
+		})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+		sourceMap := report.SourceMap
+		if sourceMap == nil {
+			t.Fatal("expected the source map, got nil")
+		}
+
+		assertDeepEqual(t, SourceLocation{Pointer: "/paths/~1pet~1findByStatus/get", Line: 164, Column: 7}, sourceMap.Functions["findPetsByStatus"])
+		assertDeepEqual(t, SourceLocation{Pointer: "/components/schemas/Pet", Line: 2938, Column: 14}, sourceMap.ObjectTypes["Pet"].SourceLocation)
+		assertDeepEqual(t, SourceLocation{Pointer: "/components/schemas/Pet/properties/name", Line: 2947, Column: 19}, sourceMap.ObjectTypes["Pet"].Fields["name"])
+		assertDeepEqual(t, SourceLocation{Pointer: "/components/schemas/Pet/properties/status", Line: 2975, Column: 21}, sourceMap.ScalarTypes["PetStatus"])
+
+		for _, proc := range output.Procedures {
+			if _, ok := sourceMap.Procedures[proc.Name]; !ok {
+				t.Errorf("expected the source location of procedure %s", proc.Name)
+			}
+		}
+		for key := range sourceMap.ObjectTypes {
+			if _, ok := output.ObjectTypes[key]; !ok {
+				t.Errorf("expected the object type %s exists in the schema", key)
+			}
+		}
+	})
+
+	t.Run("petstore2", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore2/swagger.json")
+		assertNoError(t, err)
+
+		output, report, errs := OpenAPIv2ToNDCSchemaWithReport(sourceBytes, ConvertOptions{SourceMap: true})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+		assertDeepEqual(t, SourceLocation{Pointer: "/paths/~1pet~1findByStatus/get", Line: 123, Column: 7}, report.SourceMap.Functions["findPetsByStatus"])
+	})
+
+	t.Run("collections", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/collections/openapi3.yaml")
+		assertNoError(t, err)
+
+		output, report, errs := OpenAPIv3ToNDCSchemaWithReport(sourceBytes, ConvertOptions{
+			DetectCollections: true,
+			SourceMap:         true,
+		})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+		if _, ok := report.SourceMap.Collections["listPets"]; !ok {
+			t.Errorf("expected the source location of collection listPets, got: %+v", report.SourceMap.Collections)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore2/swagger.json")
+		assertNoError(t, err)
+
+		_, report, _ := OpenAPIv2ToNDCSchemaWithReport(sourceBytes, ConvertOptions{})
+		if report.SourceMap != nil {
+			t.Errorf("expected no source map, got: %+v", report.SourceMap)
+		}
+	})
+}
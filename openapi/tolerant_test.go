@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+)
+
+func TestTolerantMode(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Source         string
+		Convert        func(input []byte, options ConvertOptions) (*rest.NDCRestSchema, *ConversionReport, []error)
+		ExpectedErrors []OperationError
+	}{
+		{
+			Name:    "openapi3",
+			Source:  "testdata/tolerant/openapi3.yaml",
+			Convert: OpenAPIv3ToNDCSchemaWithReport,
+			ExpectedErrors: []OperationError{
+				{Path: "/pets", Method: "post", OperationID: "createPet", Pointer: "/paths/~1pets/post"},
+				{Path: "/pets/{petId}", Method: "get", OperationID: "getPet", Pointer: "/paths/~1pets~1{petId}/get"},
+			},
+		},
+		{
+			Name:    "openapi2",
+			Source:  "testdata/tolerant/swagger.yaml",
+			Convert: OpenAPIv2ToNDCSchemaWithReport,
+			ExpectedErrors: []OperationError{
+				{Path: "/pets", Method: "post", OperationID: "createPet", Pointer: "/paths/~1pets/post"},
+				{Path: "/pets/{petId}", Method: "delete", OperationID: "deletePet", Pointer: "/paths/~1pets~1{petId}/delete"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assertNoError(t, err)
+
+			// strict mode stops at the first error
+			output, _, errs := tc.Convert(sourceBytes, ConvertOptions{})
+			if output != nil || len(errs) != 1 {
+				t.Fatalf("expected 1 error without the schema in strict mode, got: %v", errs)
+			}
+
+			output, report, errs := tc.Convert(sourceBytes, ConvertOptions{Tolerant: true})
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+			assertDeepEqual(t, 1, len(output.Functions))
+			assertDeepEqual(t, "listPets", output.Functions[0].Name)
+			assertDeepEqual(t, 0, len(output.Procedures))
+			assertDeepEqual(t, len(tc.ExpectedErrors), len(errs))
+			for i, expected := range tc.ExpectedErrors {
+				var opErr OperationError
+				if !errors.As(errs[i], &opErr) {
+					t.Fatalf("expected OperationError, got: %s", errs[i])
+				}
+				if opErr.Err == nil {
+					t.Errorf("expected the original error of %s %s", expected.Method, expected.Path)
+				}
+				opErr.Err = nil
+				assertDeepEqual(t, expected, opErr)
+				assertDeepEqual(t, SkippedOperation{
+					Path:        expected.Path,
+					Method:      expected.Method,
+					OperationID: expected.OperationID,
+					Pointer:     expected.Pointer,
+					Reason:      errs[i].(OperationError).Err.Error(),
+				}, report.SkippedOperations[i])
+			}
+		})
+	}
+}
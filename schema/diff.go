@@ -0,0 +1,373 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// SchemaChange represents a change between two NDC REST schemas
+type SchemaChange struct {
+	// The location of the changed element, e.g. functions.findPets.arguments.status
+	Path string `json:"path" yaml:"path"`
+	// Human-readable description of the change
+	Message string `json:"message" yaml:"message"`
+	// The change breaks existing consumers
+	Breaking bool `json:"breaking" yaml:"breaking"`
+}
+
+// String implements the fmt.Stringer interface
+func (sc SchemaChange) String() string {
+	return fmt.Sprintf("%s: %s", sc.Path, sc.Message)
+}
+
+// SchemaDiff represents the list of changes between two NDC REST schemas
+type SchemaDiff struct {
+	Breaking    []SchemaChange `json:"breaking" yaml:"breaking"`
+	NonBreaking []SchemaChange `json:"nonBreaking" yaml:"nonBreaking"`
+}
+
+// HasBreakingChanges checks if there is any breaking change
+func (sd SchemaDiff) HasBreakingChanges() bool {
+	return len(sd.Breaking) > 0
+}
+
+// IsEmpty checks if both schemas are equal
+func (sd SchemaDiff) IsEmpty() bool {
+	return len(sd.Breaking) == 0 && len(sd.NonBreaking) == 0
+}
+
+// String implements the fmt.Stringer interface
+func (sd SchemaDiff) String() string {
+	if sd.IsEmpty() {
+		return "No changes.\n"
+	}
+	var sb strings.Builder
+	if len(sd.Breaking) > 0 {
+		sb.WriteString(fmt.Sprintf("Breaking changes (%d):\n", len(sd.Breaking)))
+		for _, change := range sd.Breaking {
+			sb.WriteString(fmt.Sprintf("  - %s\n", change))
+		}
+	}
+	if len(sd.NonBreaking) > 0 {
+		if len(sd.Breaking) > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("Non-breaking changes (%d):\n", len(sd.NonBreaking)))
+		for _, change := range sd.NonBreaking {
+			sb.WriteString(fmt.Sprintf("  - %s\n", change))
+		}
+	}
+	return sb.String()
+}
+
+func (sd *SchemaDiff) add(breaking bool, path string, format string, args ...any) {
+	change := SchemaChange{
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	}
+	if breaking {
+		sd.Breaking = append(sd.Breaking, change)
+	} else {
+		sd.NonBreaking = append(sd.NonBreaking, change)
+	}
+}
+
+// DiffNDCRestSchema compares two NDC REST schemas and classifies changes as breaking or non-breaking
+// from the point of view of API consumers
+func DiffNDCRestSchema(source *NDCRestSchema, target *NDCRestSchema) *SchemaDiff {
+	sd := &SchemaDiff{}
+	inputTypes := target.getArgumentTypeNames()
+	outputTypes := target.getResultTypeNames()
+
+	sourceCollections := make(map[string]*RESTCollectionInfo)
+	for _, collection := range source.Collections {
+		sourceCollections[collection.Name] = collection
+	}
+	targetCollections := make(map[string]*RESTCollectionInfo)
+	for _, collection := range target.Collections {
+		targetCollections[collection.Name] = collection
+	}
+	for _, key := range getSortedKeys(sourceCollections) {
+		path := fmt.Sprintf("collections.%s", key)
+		sourceCollection := sourceCollections[key]
+		targetCollection, ok := targetCollections[key]
+		if !ok {
+			sd.add(true, path, "collection was removed")
+			continue
+		}
+		sd.diffOperation(path, sourceCollection.Arguments, targetCollection.Arguments, schema.NewNamedType(sourceCollection.Type).Encode(), schema.NewNamedType(targetCollection.Type).Encode())
+		sd.diffRequest(path, sourceCollection.Request, targetCollection.Request)
+	}
+	for _, key := range getSortedKeys(targetCollections) {
+		if _, ok := sourceCollections[key]; !ok {
+			sd.add(false, fmt.Sprintf("collections.%s", key), "collection was added")
+		}
+	}
+
+	sourceFunctions := make(map[string]*RESTFunctionInfo)
+	for _, fn := range source.Functions {
+		sourceFunctions[fn.Name] = fn
+	}
+	targetFunctions := make(map[string]*RESTFunctionInfo)
+	for _, fn := range target.Functions {
+		targetFunctions[fn.Name] = fn
+	}
+	for _, key := range getSortedKeys(sourceFunctions) {
+		path := fmt.Sprintf("functions.%s", key)
+		sourceFn := sourceFunctions[key]
+		targetFn, ok := targetFunctions[key]
+		if !ok {
+			sd.add(true, path, "function was removed")
+			continue
+		}
+		sd.diffOperation(path, sourceFn.Arguments, targetFn.Arguments, sourceFn.ResultType, targetFn.ResultType)
+		sd.diffRequest(path, sourceFn.Request, targetFn.Request)
+	}
+	for _, key := range getSortedKeys(targetFunctions) {
+		if _, ok := sourceFunctions[key]; !ok {
+			sd.add(false, fmt.Sprintf("functions.%s", key), "function was added")
+		}
+	}
+
+	sourceProcedures := make(map[string]*RESTProcedureInfo)
+	for _, proc := range source.Procedures {
+		sourceProcedures[proc.Name] = proc
+	}
+	targetProcedures := make(map[string]*RESTProcedureInfo)
+	for _, proc := range target.Procedures {
+		targetProcedures[proc.Name] = proc
+	}
+	for _, key := range getSortedKeys(sourceProcedures) {
+		path := fmt.Sprintf("procedures.%s", key)
+		sourceProc := sourceProcedures[key]
+		targetProc, ok := targetProcedures[key]
+		if !ok {
+			sd.add(true, path, "procedure was removed")
+			continue
+		}
+		sd.diffOperation(path, sourceProc.Arguments, targetProc.Arguments, sourceProc.ResultType, targetProc.ResultType)
+		sd.diffRequest(path, sourceProc.Request, targetProc.Request)
+	}
+	for _, key := range getSortedKeys(targetProcedures) {
+		if _, ok := sourceProcedures[key]; !ok {
+			sd.add(false, fmt.Sprintf("procedures.%s", key), "procedure was added")
+		}
+	}
+
+	for _, key := range getSortedKeys(source.ObjectTypes) {
+		path := fmt.Sprintf("object_types.%s", key)
+		targetObject, ok := target.ObjectTypes[key]
+		if !ok {
+			sd.add(true, path, "object type was removed")
+			continue
+		}
+		sd.diffObjectType(path, source.ObjectTypes[key], targetObject, slices.Contains(inputTypes, key), slices.Contains(outputTypes, key))
+	}
+	for _, key := range getSortedKeys(target.ObjectTypes) {
+		if _, ok := source.ObjectTypes[key]; !ok {
+			sd.add(false, fmt.Sprintf("object_types.%s", key), "object type was added")
+		}
+	}
+
+	for _, key := range getSortedKeys(source.ScalarTypes) {
+		path := fmt.Sprintf("scalar_types.%s", key)
+		targetScalar, ok := target.ScalarTypes[key]
+		if !ok {
+			sd.add(true, path, "scalar type was removed")
+			continue
+		}
+		sd.diffScalarType(path, source.ScalarTypes[key], targetScalar)
+	}
+	for _, key := range getSortedKeys(target.ScalarTypes) {
+		if _, ok := source.ScalarTypes[key]; !ok {
+			sd.add(false, fmt.Sprintf("scalar_types.%s", key), "scalar type was added")
+		}
+	}
+
+	return sd
+}
+
+func (sd *SchemaDiff) diffOperation(path string, sourceArguments map[string]schema.ArgumentInfo, targetArguments map[string]schema.ArgumentInfo, sourceResultType schema.Type, targetResultType schema.Type) {
+	for _, key := range getSortedKeys(sourceArguments) {
+		argPath := fmt.Sprintf("%s.arguments.%s", path, key)
+		targetArg, ok := targetArguments[key]
+		if !ok {
+			sd.add(true, argPath, "argument was removed")
+			continue
+		}
+		sd.diffType(argPath, sourceArguments[key].Type, targetArg.Type, true, false)
+	}
+	for _, key := range getSortedKeys(targetArguments) {
+		if _, ok := sourceArguments[key]; ok {
+			continue
+		}
+		argPath := fmt.Sprintf("%s.arguments.%s", path, key)
+		if isNullableSchemaType(targetArguments[key].Type) {
+			sd.add(false, argPath, "optional argument was added")
+		} else {
+			sd.add(true, argPath, "required argument was added")
+		}
+	}
+
+	sd.diffType(fmt.Sprintf("%s.result_type", path), sourceResultType, targetResultType, false, true)
+}
+
+func (sd *SchemaDiff) diffRequest(path string, source *Request, target *Request) {
+	if source == nil || target == nil {
+		if source != target {
+			sd.add(true, fmt.Sprintf("%s.request", path), "request was changed")
+		}
+		return
+	}
+	if source.URL != target.URL {
+		sd.add(true, fmt.Sprintf("%s.request.url", path), "url was changed from %s to %s", source.URL, target.URL)
+	}
+	if source.Method != target.Method {
+		sd.add(true, fmt.Sprintf("%s.request.method", path), "method was changed from %s to %s", source.Method, target.Method)
+	}
+}
+
+func (sd *SchemaDiff) diffObjectType(path string, source schema.ObjectType, target schema.ObjectType, isInput bool, isOutput bool) {
+	for _, key := range getSortedKeys(source.Fields) {
+		fieldPath := fmt.Sprintf("%s.%s", path, key)
+		targetField, ok := target.Fields[key]
+		if !ok {
+			sd.add(true, fieldPath, "field was removed")
+			continue
+		}
+		sd.diffType(fieldPath, source.Fields[key].Type, targetField.Type, isInput, isOutput)
+	}
+	for _, key := range getSortedKeys(target.Fields) {
+		if _, ok := source.Fields[key]; ok {
+			continue
+		}
+		fieldPath := fmt.Sprintf("%s.%s", path, key)
+		if isInput && !isNullableSchemaType(target.Fields[key].Type) {
+			sd.add(true, fieldPath, "required field was added to an input type")
+		} else {
+			sd.add(false, fieldPath, "field was added")
+		}
+	}
+}
+
+// diffType compares two types. Nullability changes are breaking when
+// a nullable input becomes required, or a required output becomes nullable
+func (sd *SchemaDiff) diffType(path string, source schema.Type, target schema.Type, isInput bool, isOutput bool) {
+	sourceNullable := isNullableSchemaType(source)
+	targetNullable := isNullableSchemaType(target)
+	sourceUnderlying := schema.UnwrapNullableType(source)
+	targetUnderlying := schema.UnwrapNullableType(target)
+	if sourceUnderlying.String() != targetUnderlying.String() {
+		sd.add(true, path, "type was changed from %s to %s", source, target)
+		return
+	}
+	if sourceNullable == targetNullable {
+		return
+	}
+	if sourceNullable {
+		sd.add(isInput, path, "type was changed from nullable to required")
+	} else {
+		sd.add(isOutput, path, "type was changed from required to nullable")
+	}
+}
+
+func (sd *SchemaDiff) diffScalarType(path string, source schema.ScalarType, target schema.ScalarType) {
+	sourceType, _ := source.Representation.Type()
+	targetType, _ := target.Representation.Type()
+	if sourceType != targetType {
+		sd.add(true, path, "representation was changed from %s to %s", sourceType, targetType)
+		return
+	}
+	if sourceType != schema.TypeRepresentationTypeEnum {
+		return
+	}
+	sourceEnum, err := source.Representation.AsEnum()
+	if err != nil {
+		return
+	}
+	targetEnum, err := target.Representation.AsEnum()
+	if err != nil {
+		return
+	}
+	var removedValues, addedValues []string
+	for _, value := range sourceEnum.OneOf {
+		if !slices.Contains(targetEnum.OneOf, value) {
+			removedValues = append(removedValues, value)
+		}
+	}
+	for _, value := range targetEnum.OneOf {
+		if !slices.Contains(sourceEnum.OneOf, value) {
+			addedValues = append(addedValues, value)
+		}
+	}
+	if len(removedValues) > 0 {
+		sd.add(true, path, "enum values were removed: %s", strings.Join(removedValues, ", "))
+	}
+	if len(addedValues) > 0 {
+		sd.add(false, path, "enum values were added: %s", strings.Join(addedValues, ", "))
+	}
+}
+
+// get names of object types which are used in arguments, including nested fields
+func (ndc NDCRestSchema) getArgumentTypeNames() []string {
+	var results []string
+	for _, collection := range ndc.Collections {
+		for _, arg := range collection.Arguments {
+			results = ndc.appendReferencedTypeNames(results, arg.Type)
+		}
+	}
+	for _, fn := range ndc.Functions {
+		for _, arg := range fn.Arguments {
+			results = ndc.appendReferencedTypeNames(results, arg.Type)
+		}
+	}
+	for _, proc := range ndc.Procedures {
+		for _, arg := range proc.Arguments {
+			results = ndc.appendReferencedTypeNames(results, arg.Type)
+		}
+	}
+	return results
+}
+
+// get names of object types which are used in result types, including nested fields
+func (ndc NDCRestSchema) getResultTypeNames() []string {
+	var results []string
+	for _, collection := range ndc.Collections {
+		results = ndc.appendReferencedTypeNames(results, schema.NewNamedType(collection.Type).Encode())
+	}
+	for _, fn := range ndc.Functions {
+		results = ndc.appendReferencedTypeNames(results, fn.ResultType)
+	}
+	for _, proc := range ndc.Procedures {
+		results = ndc.appendReferencedTypeNames(results, proc.ResultType)
+	}
+	return results
+}
+
+func (ndc NDCRestSchema) appendReferencedTypeNames(results []string, input schema.Type) []string {
+	namedType := schema.GetUnderlyingNamedType(input)
+	if namedType == nil || slices.Contains(results, namedType.Name) {
+		return results
+	}
+	object, ok := ndc.ObjectTypes[namedType.Name]
+	if !ok {
+		return results
+	}
+	results = append(results, namedType.Name)
+	for _, field := range object.Fields {
+		results = ndc.appendReferencedTypeNames(results, field.Type)
+	}
+	return results
+}
+
+func isNullableSchemaType(input schema.Type) bool {
+	if len(input) == 0 {
+		return false
+	}
+	_, ok := input.Interface().(*schema.NullableType)
+	return ok
+}
@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffNDCRestSchema(t *testing.T) {
+	source := `{
+		"collections": [
+			{
+				"request": { "url": "/pets", "method": "get" },
+				"arguments": {},
+				"name": "pets",
+				"type": "Pet",
+				"uniqueness_constraints": {},
+				"foreign_keys": {}
+			},
+			{
+				"request": { "url": "/pets/archived", "method": "get" },
+				"arguments": {},
+				"name": "archivedPets",
+				"type": "Pet",
+				"uniqueness_constraints": {},
+				"foreign_keys": {}
+			}
+		],
+		"functions": [
+			{
+				"request": { "url": "/pets", "method": "get" },
+				"arguments": {
+					"status": { "type": { "type": "nullable", "underlying_type": { "name": "PetStatus", "type": "named" } } },
+					"limit": { "type": { "type": "nullable", "underlying_type": { "name": "Int32", "type": "named" } } }
+				},
+				"name": "findPets",
+				"result_type": { "type": "array", "element_type": { "name": "Pet", "type": "named" } }
+			},
+			{
+				"request": { "url": "/pets/{id}", "method": "get" },
+				"arguments": {},
+				"name": "getPet",
+				"result_type": { "name": "Pet", "type": "named" }
+			}
+		],
+		"object_types": {
+			"Pet": {
+				"fields": {
+					"id": { "type": { "name": "Int32", "type": "named" } },
+					"name": { "type": { "name": "String", "type": "named" } },
+					"tag": { "type": { "type": "nullable", "underlying_type": { "name": "String", "type": "named" } } }
+				}
+			}
+		},
+		"procedures": [],
+		"scalar_types": {
+			"Int32": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "int32" } },
+			"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } },
+			"PetStatus": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "enum", "one_of": ["available", "pending", "sold"] } }
+		}
+	}`
+
+	target := `{
+		"collections": [
+			{
+				"request": { "url": "/pets/archived", "method": "post" },
+				"arguments": {},
+				"name": "archivedPets",
+				"type": "Pet",
+				"uniqueness_constraints": {},
+				"foreign_keys": {}
+			}
+		],
+		"functions": [
+			{
+				"request": { "url": "/v2/pets", "method": "get" },
+				"arguments": {
+					"status": { "type": { "name": "PetStatus", "type": "named" } },
+					"offset": { "type": { "type": "nullable", "underlying_type": { "name": "Int32", "type": "named" } } }
+				},
+				"name": "findPets",
+				"result_type": { "type": "array", "element_type": { "name": "Pet", "type": "named" } }
+			}
+		],
+		"object_types": {
+			"Pet": {
+				"fields": {
+					"id": { "type": { "name": "Int32", "type": "named" } },
+					"name": { "type": { "type": "nullable", "underlying_type": { "name": "String", "type": "named" } } },
+					"tag": { "type": { "name": "String", "type": "named" } }
+				}
+			}
+		},
+		"procedures": [
+			{
+				"request": { "url": "/pets", "method": "post" },
+				"arguments": {},
+				"name": "addPet",
+				"result_type": { "name": "Pet", "type": "named" }
+			}
+		],
+		"scalar_types": {
+			"Int32": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "int32" } },
+			"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } },
+			"PetStatus": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "enum", "one_of": ["available", "sold", "archived"] } }
+		}
+	}`
+
+	var sourceSchema, targetSchema NDCRestSchema
+	if err := json.Unmarshal([]byte(source), &sourceSchema); err != nil {
+		t.Fatalf("failed to decode source schema: %s", err)
+	}
+	if err := json.Unmarshal([]byte(target), &targetSchema); err != nil {
+		t.Fatalf("failed to decode target schema: %s", err)
+	}
+
+	result := DiffNDCRestSchema(&sourceSchema, &targetSchema)
+	assertDeepEqual(t, []SchemaChange{
+		{Path: "collections.archivedPets.request.method", Message: "method was changed from get to post", Breaking: true},
+		{Path: "collections.pets", Message: "collection was removed", Breaking: true},
+		{Path: "functions.findPets.arguments.limit", Message: "argument was removed", Breaking: true},
+		{Path: "functions.findPets.arguments.status", Message: "type was changed from nullable to required", Breaking: true},
+		{Path: "functions.findPets.request.url", Message: "url was changed from /pets to /v2/pets", Breaking: true},
+		{Path: "functions.getPet", Message: "function was removed", Breaking: true},
+		{Path: "object_types.Pet.name", Message: "type was changed from required to nullable", Breaking: true},
+		{Path: "scalar_types.PetStatus", Message: "enum values were removed: pending", Breaking: true},
+	}, result.Breaking)
+	assertDeepEqual(t, []SchemaChange{
+		{Path: "functions.findPets.arguments.offset", Message: "optional argument was added", Breaking: false},
+		{Path: "procedures.addPet", Message: "procedure was added", Breaking: false},
+		{Path: "object_types.Pet.tag", Message: "type was changed from nullable to required", Breaking: false},
+		{Path: "scalar_types.PetStatus", Message: "enum values were added: archived", Breaking: false},
+	}, result.NonBreaking)
+
+	noChanges := DiffNDCRestSchema(&sourceSchema, &sourceSchema)
+	if !noChanges.IsEmpty() {
+		t.Fatalf("expected no changes, got: %s", noChanges)
+	}
+}
@@ -195,45 +195,63 @@ func ParseParameterLocation(input string) (ParameterLocation, error) {
 type ScalarName string
 
 const (
-	ScalarBoolean     ScalarName = "Boolean"
-	ScalarString      ScalarName = "String"
-	ScalarInt32       ScalarName = "Int32"
-	ScalarInt64       ScalarName = "Int64"
-	ScalarFloat32     ScalarName = "Float32"
-	ScalarFloat64     ScalarName = "Float64"
-	ScalarBigDecimal  ScalarName = "BigDecimal"
-	ScalarUUID        ScalarName = "UUID"
-	ScalarDate        ScalarName = "Date"
-	ScalarTimestampTZ ScalarName = "TimestampTZ"
-	ScalarBytes       ScalarName = "Bytes"
-	ScalarBinary      ScalarName = "Binary"
-	ScalarJSON        ScalarName = "JSON"
-	ScalarUnixTime    ScalarName = "UnixTime"
-	ScalarEmail       ScalarName = "Email"
-	ScalarURI         ScalarName = "URI"
-	ScalarIPV4        ScalarName = "IPv4"
-	ScalarIPV6        ScalarName = "IPv6"
+	ScalarBoolean      ScalarName = "Boolean"
+	ScalarString       ScalarName = "String"
+	ScalarInt8         ScalarName = "Int8"
+	ScalarInt16        ScalarName = "Int16"
+	ScalarInt32        ScalarName = "Int32"
+	ScalarInt64        ScalarName = "Int64"
+	ScalarBigInteger   ScalarName = "BigInteger"
+	ScalarFloat32      ScalarName = "Float32"
+	ScalarFloat64      ScalarName = "Float64"
+	ScalarBigDecimal   ScalarName = "BigDecimal"
+	ScalarUUID         ScalarName = "UUID"
+	ScalarDate         ScalarName = "Date"
+	ScalarTime         ScalarName = "Time"
+	ScalarTimestamp    ScalarName = "Timestamp"
+	ScalarTimestampTZ  ScalarName = "TimestampTZ"
+	ScalarDuration     ScalarName = "Duration"
+	ScalarBytes        ScalarName = "Bytes"
+	ScalarBinary       ScalarName = "Binary"
+	ScalarJSON         ScalarName = "JSON"
+	ScalarUnixTime     ScalarName = "UnixTime"
+	ScalarEmail        ScalarName = "Email"
+	ScalarURI          ScalarName = "URI"
+	ScalarURIReference ScalarName = "URIReference"
+	ScalarHostname     ScalarName = "Hostname"
+	ScalarIPV4         ScalarName = "IPv4"
+	ScalarIPV6         ScalarName = "IPv6"
+	ScalarPassword     ScalarName = "Password"
 )
 
 var scalarName_enums = []ScalarName{
 	ScalarBoolean,
 	ScalarString,
+	ScalarInt8,
+	ScalarInt16,
 	ScalarInt32,
 	ScalarInt64,
+	ScalarBigInteger,
 	ScalarFloat32,
 	ScalarFloat64,
 	ScalarBigDecimal,
 	ScalarUUID,
 	ScalarDate,
+	ScalarTime,
+	ScalarTimestamp,
 	ScalarTimestampTZ,
+	ScalarDuration,
 	ScalarBytes,
 	ScalarBinary,
 	ScalarJSON,
 	ScalarUnixTime,
 	ScalarEmail,
 	ScalarURI,
+	ScalarURIReference,
+	ScalarHostname,
 	ScalarIPV4,
 	ScalarIPV6,
+	ScalarPassword,
 }
 
 // IsDefaultScalar checks if the scalar name is
@@ -323,3 +341,209 @@ func ParseParameterEncodingStyle(input string) (ParameterEncodingStyle, error) {
 	}
 	return result, nil
 }
+
+// MergeConflictPolicy represents the strategy to resolve name collisions when merging schemas
+type MergeConflictPolicy string
+
+const (
+	// MergeConflictError returns an error if there is any name collision
+	MergeConflictError MergeConflictPolicy = "error"
+	// MergeConflictPrefix adds the source name as the prefix to conflicted names
+	MergeConflictPrefix MergeConflictPolicy = "prefix"
+	// MergeConflictKeepFirst keeps the first element and skips others with the same name
+	MergeConflictKeepFirst MergeConflictPolicy = "keep-first"
+)
+
+var mergeConflictPolicy_enums = []MergeConflictPolicy{MergeConflictError, MergeConflictPrefix, MergeConflictKeepFirst}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j MergeConflictPolicy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(mergeConflictPolicy_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *MergeConflictPolicy) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseMergeConflictPolicy(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+	return nil
+}
+
+// IsValid checks if the policy enum is valid
+func (j MergeConflictPolicy) IsValid() bool {
+	return slices.Contains(mergeConflictPolicy_enums, j)
+}
+
+// ParseMergeConflictPolicy parses MergeConflictPolicy from string
+func ParseMergeConflictPolicy(input string) (MergeConflictPolicy, error) {
+	result := MergeConflictPolicy(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid MergeConflictPolicy. Expected %+v, got <%s>", mergeConflictPolicy_enums, input)
+	}
+	return result, nil
+}
+
+// PaginationStyle represents the strategy that a list endpoint uses to paginate items
+type PaginationStyle string
+
+const (
+	// PaginationOffset skips items with an offset parameter
+	PaginationOffset PaginationStyle = "offset"
+	// PaginationPage selects items with a page number parameter
+	PaginationPage PaginationStyle = "page"
+	// PaginationCursor selects items after an opaque cursor that is returned in the previous page
+	PaginationCursor PaginationStyle = "cursor"
+)
+
+var paginationStyle_enums = []PaginationStyle{PaginationOffset, PaginationPage, PaginationCursor}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j PaginationStyle) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(paginationStyle_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *PaginationStyle) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParsePaginationStyle(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+	return nil
+}
+
+// IsValid checks if the style enum is valid
+func (j PaginationStyle) IsValid() bool {
+	return slices.Contains(paginationStyle_enums, j)
+}
+
+// ParsePaginationStyle parses PaginationStyle from string
+func ParsePaginationStyle(input string) (PaginationStyle, error) {
+	result := PaginationStyle(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid PaginationStyle. Expected %+v, got <%s>", paginationStyle_enums, input)
+	}
+	return result, nil
+}
+
+// OperationKind represents the kind of NDC operation that an API operation is converted to
+type OperationKind string
+
+const (
+	// OperationFunction converts the API operation to a query function
+	OperationFunction OperationKind = "function"
+	// OperationProcedure converts the API operation to a mutation procedure
+	OperationProcedure OperationKind = "procedure"
+)
+
+var operationKind_enums = []OperationKind{OperationFunction, OperationProcedure}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j OperationKind) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(operationKind_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OperationKind) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseOperationKind(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+	return nil
+}
+
+// IsValid checks if the kind enum is valid
+func (j OperationKind) IsValid() bool {
+	return slices.Contains(operationKind_enums, j)
+}
+
+// ParseOperationKind parses OperationKind from string
+func ParseOperationKind(input string) (OperationKind, error) {
+	result := OperationKind(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid OperationKind. Expected %+v, got <%s>", operationKind_enums, input)
+	}
+	return result, nil
+}
+
+// NumberRepresentation represents the JSON representation of int64, uint64 and decimal values
+type NumberRepresentation string
+
+const (
+	// NumberRepresentationNumber encodes values as JSON numbers
+	NumberRepresentationNumber NumberRepresentation = "number"
+	// NumberRepresentationString encodes values as JSON strings to avoid precision loss
+	NumberRepresentationString NumberRepresentation = "string"
+	// NumberRepresentationBigDecimal encodes values as arbitrary-precision JSON numbers
+	NumberRepresentationBigDecimal NumberRepresentation = "bigdecimal"
+)
+
+var numberRepresentation_enums = []NumberRepresentation{NumberRepresentationNumber, NumberRepresentationString, NumberRepresentationBigDecimal}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j NumberRepresentation) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(numberRepresentation_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *NumberRepresentation) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseNumberRepresentation(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+	return nil
+}
+
+// IsValid checks if the representation enum is valid
+func (j NumberRepresentation) IsValid() bool {
+	return slices.Contains(numberRepresentation_enums, j)
+}
+
+// ParseNumberRepresentation parses NumberRepresentation from string
+func ParseNumberRepresentation(input string) (NumberRepresentation, error) {
+	result := NumberRepresentation(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid NumberRepresentation. Expected %+v, got <%s>", numberRepresentation_enums, input)
+	}
+	return result, nil
+}
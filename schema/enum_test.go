@@ -72,3 +72,45 @@ func TestParameterEncodingStyle(t *testing.T) {
 		t.Fatalf("expected string, got: %s", got.JSONSchema().Type)
 	}
 }
+
+func TestPaginationStyle(t *testing.T) {
+	rawValue := "cursor"
+	var got PaginationStyle
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`"%s"`, rawValue)), &got); err != nil {
+		t.Fatal(err.Error())
+	}
+	if got != PaginationCursor {
+		t.Fatalf("expected %s, got: %s", rawValue, got)
+	}
+	if err := json.Unmarshal([]byte(`"token"`), &got); err == nil {
+		t.Fatal("expected invalid PaginationStyle error, got nil")
+	}
+}
+
+func TestOperationKind(t *testing.T) {
+	rawValue := "function"
+	var got OperationKind
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`"%s"`, rawValue)), &got); err != nil {
+		t.Fatal(err.Error())
+	}
+	if got != OperationFunction {
+		t.Fatalf("expected %s, got: %s", rawValue, got)
+	}
+	if _, err := ParseOperationKind("query"); err == nil {
+		t.Fatal("expected invalid OperationKind error, got nil")
+	}
+}
+
+func TestNumberRepresentation(t *testing.T) {
+	rawValue := "string"
+	var got NumberRepresentation
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`"%s"`, rawValue)), &got); err != nil {
+		t.Fatal(err.Error())
+	}
+	if got != NumberRepresentationString {
+		t.Fatalf("expected %s, got: %s", rawValue, got)
+	}
+	if _, err := ParseNumberRepresentation("int64"); err == nil {
+		t.Fatal("expected invalid NumberRepresentation error, got nil")
+	}
+}
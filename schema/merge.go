@@ -0,0 +1,551 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// NDCRestSchemaSource represents a named NDC REST schema to be merged
+type NDCRestSchemaSource struct {
+	// The name of the source that is used to prefix conflicted names
+	Name   string
+	Schema *NDCRestSchema
+}
+
+// MergeNDCRestSchemas combines many NDC REST schemas into one.
+// Servers, security schemes and other server settings of each source are attached to its operations,
+// so requests are still sent to the original API after merging.
+// Settings of the merged schema are taken from the first source.
+// Object and scalar types with the same name and definition are shared between sources.
+// With the keep-first policy, an operation fails to merge if it uses a type that is replaced by a different type of a previous schema.
+// Other name collisions of collections, functions, procedures, object and scalar types are resolved by the conflict policy.
+func MergeNDCRestSchemas(sources []NDCRestSchemaSource, policy MergeConflictPolicy) (*NDCRestSchema, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("require at least 1 schema to merge")
+	}
+	if policy == "" {
+		policy = MergeConflictError
+	}
+	if _, err := ParseMergeConflictPolicy(string(policy)); err != nil {
+		return nil, err
+	}
+
+	result := NewNDCRestSchema()
+	var errs []error
+	for i, source := range sources {
+		if source.Schema == nil {
+			return nil, fmt.Errorf("schema of source %s is empty", source.Name)
+		}
+		if policy == MergeConflictPrefix && source.Name == "" {
+			return nil, fmt.Errorf("the name of source %d is required to prefix conflicted names", i)
+		}
+		sm, err := source.Schema.clone()
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			result.Settings = sm.Settings
+		}
+		sm.attachSettingsToRequests()
+
+		typeRenames, typeErrs := result.resolveTypeConflicts(sm, policy, source.Name)
+		errs = append(errs, typeErrs...)
+		// types of the source that are replaced by a different type of a previous schema with the keep-first policy
+		replacedTypes := sm.getTypesReferencing(typeRenames)
+		sm.renameTypes(typeRenames)
+
+		for key, object := range sm.ObjectTypes {
+			if _, ok := result.ObjectTypes[key]; !ok {
+				result.ObjectTypes[key] = object
+			}
+		}
+		for key, scalar := range sm.ScalarTypes {
+			if _, ok := result.ScalarTypes[key]; !ok {
+				result.ScalarTypes[key] = scalar
+			}
+		}
+
+		for _, collection := range sm.Collections {
+			if !result.hasOperationName(collection.Name, "collection") {
+				if err := checkReplacedTypes(source.Name, "collection", collection.Name, replacedTypes, collection.Arguments, schema.NewNamedType(collection.Type).Encode(), collection.Request); err != nil {
+					errs = append(errs, err)
+				}
+				result.Collections = append(result.Collections, collection)
+				continue
+			}
+			newName, err := result.resolveOperationConflict(policy, source.Name, "collection", collection.Name)
+			if err != nil {
+				errs = append(errs, err)
+			} else if newName != "" {
+				collection.Name = newName
+				result.Collections = append(result.Collections, collection)
+			}
+		}
+		for _, fn := range sm.Functions {
+			if !result.hasOperationName(fn.Name, "function") {
+				if err := checkReplacedTypes(source.Name, "function", fn.Name, replacedTypes, fn.Arguments, fn.ResultType, fn.Request); err != nil {
+					errs = append(errs, err)
+				}
+				result.Functions = append(result.Functions, fn)
+				continue
+			}
+			newName, err := result.resolveOperationConflict(policy, source.Name, "function", fn.Name)
+			if err != nil {
+				errs = append(errs, err)
+			} else if newName != "" {
+				fn.Name = newName
+				result.Functions = append(result.Functions, fn)
+			}
+		}
+		for _, proc := range sm.Procedures {
+			if !result.hasOperationName(proc.Name, "procedure") {
+				if err := checkReplacedTypes(source.Name, "procedure", proc.Name, replacedTypes, proc.Arguments, proc.ResultType, proc.Request); err != nil {
+					errs = append(errs, err)
+				}
+				result.Procedures = append(result.Procedures, proc)
+				continue
+			}
+			newName, err := result.resolveOperationConflict(policy, source.Name, "procedure", proc.Name)
+			if err != nil {
+				errs = append(errs, err)
+			} else if newName != "" {
+				proc.Name = newName
+				result.Procedures = append(result.Procedures, proc)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// resolveTypeConflicts finds object and scalar types of the source that conflict with existing types.
+// Object types are compared after references to renamed types are rewritten,
+// so a type is only shared if its nested types are also shared
+func (ndc *NDCRestSchema) resolveTypeConflicts(sm *NDCRestSchema, policy MergeConflictPolicy, sourceName string) (map[string]string, []error) {
+	renames := make(map[string]string)
+	conflicts := make(map[string]bool)
+	var errs []error
+
+	for _, key := range getSortedKeys(sm.ScalarTypes) {
+		existing, ok := ndc.ScalarTypes[key]
+		if ok && isJSONEqual(existing, sm.ScalarTypes[key]) {
+			continue
+		}
+		if _, isObject := ndc.ObjectTypes[key]; ok || isObject {
+			conflicts[key] = true
+			if err := ndc.resolveTypeConflict(policy, sourceName, "scalar type", key, renames); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// renaming a type changes object types which refer to it, so repeat until there is no new conflict
+	for changed := true; changed; {
+		changed = false
+		for _, key := range getSortedKeys(sm.ObjectTypes) {
+			if conflicts[key] {
+				continue
+			}
+			existing, ok := ndc.ObjectTypes[key]
+			if ok && isJSONEqual(existing, renameObjectTypeFields(sm.ObjectTypes[key], renames)) {
+				continue
+			}
+			if _, isScalar := ndc.ScalarTypes[key]; ok || isScalar {
+				conflicts[key] = true
+				changed = true
+				if err := ndc.resolveTypeConflict(policy, sourceName, "object type", key, renames); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return renames, errs
+}
+
+// getTypesReferencing returns types that are replaced by types of a previous schema with the keep-first policy,
+// and object types which refer to them directly or indirectly
+func (ndc NDCRestSchema) getTypesReferencing(renames map[string]string) map[string]bool {
+	results := make(map[string]bool)
+	for oldName, newName := range renames {
+		if oldName == newName {
+			results[oldName] = true
+		}
+	}
+	if len(results) == 0 {
+		return results
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for key, object := range ndc.ObjectTypes {
+			if results[key] {
+				continue
+			}
+			for _, field := range object.Fields {
+				if namedType := schema.GetUnderlyingNamedType(field.Type); namedType != nil && results[namedType.Name] {
+					results[key] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return results
+}
+
+// checkReplacedTypes returns an error if the operation uses a type that is replaced by a different type of a previous schema
+func checkReplacedTypes(sourceName string, kind string, name string, replacedTypes map[string]bool, arguments map[string]schema.ArgumentInfo, resultType schema.Type, request *Request) error {
+	if len(replacedTypes) == 0 {
+		return nil
+	}
+	typeNames := []string{}
+	for _, ty := range append([]schema.Type{resultType}, getArgumentTypes(arguments)...) {
+		if namedType := schema.GetUnderlyingNamedType(ty); namedType != nil {
+			typeNames = append(typeNames, namedType.Name)
+		}
+	}
+	if request != nil {
+		for _, param := range request.Parameters {
+			typeNames = append(typeNames, param.Schema.getTypeNames()...)
+		}
+		if request.RequestBody != nil {
+			typeNames = append(typeNames, request.RequestBody.Schema.getTypeNames()...)
+		}
+		for _, key := range getSortedKeys(request.Response.StatusCodes) {
+			content := request.Response.StatusCodes[key]
+			if namedType := schema.GetUnderlyingNamedType(content.Type); namedType != nil {
+				typeNames = append(typeNames, namedType.Name)
+			}
+			typeNames = append(typeNames, content.Schema.getTypeNames()...)
+		}
+	}
+	for _, typeName := range typeNames {
+		if replacedTypes[typeName] {
+			return fmt.Errorf("%s: %s %s uses type %s that is replaced by a different type of a previous schema", sourceName, kind, name, typeName)
+		}
+	}
+	return nil
+}
+
+func getArgumentTypes(arguments map[string]schema.ArgumentInfo) []schema.Type {
+	results := make([]schema.Type, 0, len(arguments))
+	for _, key := range getSortedKeys(arguments) {
+		results = append(results, arguments[key].Type)
+	}
+	return results
+}
+
+func (ts *TypeSchema) getTypeNames() []string {
+	if ts == nil {
+		return nil
+	}
+	results := append([]string{ts.Type}, ts.Items.getTypeNames()...)
+	for _, key := range getSortedKeys(ts.Properties) {
+		prop := ts.Properties[key]
+		results = append(results, prop.getTypeNames()...)
+	}
+	return results
+}
+
+// renameObjectTypeFields returns a copy of the object type with renamed field types
+func renameObjectTypeFields(object schema.ObjectType, renames map[string]string) schema.ObjectType {
+	if len(renames) == 0 {
+		return object
+	}
+	result := object
+	result.Fields = make(schema.ObjectTypeFields, len(object.Fields))
+	for key, field := range object.Fields {
+		field.Type = renameNamedType(field.Type, renames)
+		result.Fields[key] = field
+	}
+	return result
+}
+
+// resolve the type name conflict. The new name is set to the rename map if the policy is prefix
+func (ndc *NDCRestSchema) resolveTypeConflict(policy MergeConflictPolicy, sourceName string, kind string, name string, renames map[string]string) error {
+	switch policy {
+	case MergeConflictPrefix:
+		newName := prefixName(sourceName, name, true)
+		_, isObject := ndc.ObjectTypes[newName]
+		_, isScalar := ndc.ScalarTypes[newName]
+		if isObject || isScalar {
+			return fmt.Errorf("%s: %s %s conflicts, and the prefixed name %s also exists", sourceName, kind, name, newName)
+		}
+		renames[name] = newName
+	case MergeConflictKeepFirst:
+		// the existing type is kept and the source's type is skipped.
+		renames[name] = name
+	default:
+		return fmt.Errorf("%s: %s %s conflicts with a previous schema", sourceName, kind, name)
+	}
+	return nil
+}
+
+// resolve the operation name conflict. Returns an empty name if the operation is skipped
+func (ndc *NDCRestSchema) resolveOperationConflict(policy MergeConflictPolicy, sourceName string, kind string, name string) (string, error) {
+	switch policy {
+	case MergeConflictPrefix:
+		newName := prefixName(sourceName, name, false)
+		if ndc.hasOperationName(newName, kind) {
+			return "", fmt.Errorf("%s: %s %s conflicts, and the prefixed name %s also exists", sourceName, kind, name, newName)
+		}
+		return newName, nil
+	case MergeConflictKeepFirst:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%s: %s %s conflicts with a previous schema", sourceName, kind, name)
+	}
+}
+
+func (ndc NDCRestSchema) hasOperationName(name string, kind string) bool {
+	switch kind {
+	case "collection":
+		for _, collection := range ndc.Collections {
+			if collection.Name == name {
+				return true
+			}
+		}
+	case "function":
+		for _, fn := range ndc.Functions {
+			if fn.Name == name {
+				return true
+			}
+		}
+	case "procedure":
+		for _, proc := range ndc.Procedures {
+			if proc.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attach global settings of the schema to servers of every request
+func (ndc *NDCRestSchema) attachSettingsToRequests() {
+	if ndc.Settings == nil {
+		return
+	}
+
+	servers := ndc.Settings.attachToServers(ndc.Settings.Servers)
+	attachRequest := func(req *Request) {
+		if req == nil {
+			return
+		}
+		if len(req.Servers) == 0 {
+			req.Servers = servers
+		} else {
+			req.Servers = ndc.Settings.attachToServers(req.Servers)
+		}
+		if len(req.Security) == 0 {
+			req.Security = ndc.Settings.Security
+		}
+	}
+	for _, collection := range ndc.Collections {
+		attachRequest(collection.Request)
+	}
+	for _, fn := range ndc.Functions {
+		attachRequest(fn.Request)
+	}
+	for _, proc := range ndc.Procedures {
+		attachRequest(proc.Request)
+	}
+}
+
+// attach global settings to servers if they aren't configured in the server
+func (rs NDCRestSettings) attachToServers(servers []ServerConfig) []ServerConfig {
+	results := make([]ServerConfig, len(servers))
+	for i, server := range servers {
+		if len(rs.Headers) > 0 {
+			headers := make(map[string]EnvString)
+			for key, value := range rs.Headers {
+				headers[key] = value
+			}
+			for key, value := range server.Headers {
+				headers[key] = value
+			}
+			server.Headers = headers
+		}
+		if len(rs.SecuritySchemes) > 0 {
+			securitySchemes := make(map[string]SecurityScheme)
+			for key, value := range rs.SecuritySchemes {
+				securitySchemes[key] = value
+			}
+			for key, value := range server.SecuritySchemes {
+				securitySchemes[key] = value
+			}
+			server.SecuritySchemes = securitySchemes
+		}
+		if len(server.Security) == 0 {
+			server.Security = rs.Security
+		}
+		if server.Timeout == nil {
+			server.Timeout = rs.Timeout
+		}
+		if server.Retry == nil {
+			server.Retry = rs.Retry
+		}
+		results[i] = server
+	}
+	return results
+}
+
+// rename type names of object and scalar types as well as their references.
+// Types which are renamed to the same name are removed
+func (ndc *NDCRestSchema) renameTypes(renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+	for oldName, newName := range renames {
+		if object, ok := ndc.ObjectTypes[oldName]; ok {
+			delete(ndc.ObjectTypes, oldName)
+			if newName != oldName {
+				ndc.ObjectTypes[newName] = object
+			}
+		}
+		if scalar, ok := ndc.ScalarTypes[oldName]; ok {
+			delete(ndc.ScalarTypes, oldName)
+			if newName != oldName {
+				ndc.ScalarTypes[newName] = scalar
+			}
+		}
+	}
+
+	for _, object := range ndc.ObjectTypes {
+		for key, field := range object.Fields {
+			field.Type = renameNamedType(field.Type, renames)
+			object.Fields[key] = field
+		}
+	}
+	for i, collection := range ndc.Collections {
+		if newName, ok := renames[collection.Type]; ok {
+			collection.Type = newName
+		}
+		for key, arg := range collection.Arguments {
+			arg.Type = renameNamedType(arg.Type, renames)
+			collection.Arguments[key] = arg
+		}
+		collection.Request.renameTypeSchemas(renames)
+		ndc.Collections[i] = collection
+	}
+	for _, fn := range ndc.Functions {
+		fn.ResultType = renameNamedType(fn.ResultType, renames)
+		for key, arg := range fn.Arguments {
+			arg.Type = renameNamedType(arg.Type, renames)
+			fn.Arguments[key] = arg
+		}
+		fn.Request.renameTypeSchemas(renames)
+	}
+	for _, proc := range ndc.Procedures {
+		proc.ResultType = renameNamedType(proc.ResultType, renames)
+		for key, arg := range proc.Arguments {
+			arg.Type = renameNamedType(arg.Type, renames)
+			proc.Arguments[key] = arg
+		}
+		proc.Request.renameTypeSchemas(renames)
+	}
+}
+
+func (r *Request) renameTypeSchemas(renames map[string]string) {
+	if r == nil {
+		return
+	}
+	for _, param := range r.Parameters {
+		param.Schema.renameTypes(renames)
+	}
+	if r.RequestBody != nil {
+		r.RequestBody.Schema.renameTypes(renames)
+	}
+	for key, content := range r.Response.StatusCodes {
+		content.Type = renameNamedType(content.Type, renames)
+		content.Schema.renameTypes(renames)
+		r.Response.StatusCodes[key] = content
+	}
+}
+
+func (ts *TypeSchema) renameTypes(renames map[string]string) {
+	if ts == nil {
+		return
+	}
+	if newName, ok := renames[ts.Type]; ok {
+		ts.Type = newName
+	}
+	ts.Items.renameTypes(renames)
+	for key, prop := range ts.Properties {
+		prop.renameTypes(renames)
+		ts.Properties[key] = prop
+	}
+	if ts.Discriminator != nil {
+		for i, variant := range ts.Discriminator.Variants {
+			if newName, ok := renames[variant.Type]; ok {
+				ts.Discriminator.Variants[i].Type = newName
+			}
+		}
+	}
+}
+
+func (ndc NDCRestSchema) clone() (*NDCRestSchema, error) {
+	rawBytes, err := json.Marshal(ndc)
+	if err != nil {
+		return nil, err
+	}
+	var result NDCRestSchema
+	if err := json.Unmarshal(rawBytes, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func renameNamedType(input schema.Type, renames map[string]string) schema.Type {
+	if len(input) == 0 {
+		return input
+	}
+	return renameNamedTypeEncoder(input.Interface(), renames).Encode()
+}
+
+func renameNamedTypeEncoder(input schema.TypeEncoder, renames map[string]string) schema.TypeEncoder {
+	switch ty := input.(type) {
+	case *schema.NullableType:
+		return schema.NewNullableType(renameNamedTypeEncoder(ty.UnderlyingType.Interface(), renames))
+	case *schema.ArrayType:
+		return schema.NewArrayType(renameNamedTypeEncoder(ty.ElementType.Interface(), renames))
+	case *schema.NamedType:
+		if newName, ok := renames[ty.Name]; ok {
+			return schema.NewNamedType(newName)
+		}
+		return ty
+	default:
+		return input
+	}
+}
+
+// prefixName adds the prefix to the name in PascalCase for types, or camelCase for operations
+func prefixName(prefix string, name string, isType bool) string {
+	if prefix == "" || name == "" {
+		return name
+	}
+	if isType {
+		return strings.ToUpper(prefix[:1]) + prefix[1:] + strings.ToUpper(name[:1]) + name[1:]
+	}
+	return strings.ToLower(prefix[:1]) + prefix[1:] + strings.ToUpper(name[:1]) + name[1:]
+}
+
+func isJSONEqual(a any, b any) bool {
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aBytes) == string(bBytes)
+}
@@ -0,0 +1,358 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+func TestMergeNDCRestSchemas(t *testing.T) {
+	rawPetStore := `{
+		"settings": {
+			"servers": [{ "url": "https://petstore.local" }],
+			"securitySchemes": {
+				"api_key": { "type": "apiKey", "value": "{{PET_STORE_API_KEY}}", "in": "header", "name": "api_key" }
+			},
+			"security": [{ "api_key": [] }]
+		},
+		"collections": [],
+		"functions": [
+			{
+				"request": { "url": "/items", "method": "get" },
+				"arguments": {},
+				"name": "getItems",
+				"result_type": { "type": "array", "element_type": { "name": "Item", "type": "named" } }
+			}
+		],
+		"object_types": {
+			"Item": {
+				"fields": {
+					"id": { "type": { "name": "String", "type": "named" } }
+				}
+			}
+		},
+		"procedures": [],
+		"scalar_types": {
+			"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } }
+		}
+	}`
+	rawStore := `{
+		"settings": {
+			"servers": [{ "url": "https://store.local" }],
+			"securitySchemes": {
+				"bearer": { "type": "http", "value": "{{STORE_TOKEN}}", "header": "Authorization", "scheme": "bearer" }
+			}
+		},
+		"collections": [],
+		"functions": [
+			{
+				"request": {
+					"url": "/items",
+					"method": "get",
+					"security": [{ "bearer": [] }],
+					"parameters": [{ "name": "filter", "in": "query", "schema": { "type": "Item", "nullable": true } }]
+				},
+				"arguments": {
+					"filter": { "type": { "type": "nullable", "underlying_type": { "name": "Item", "type": "named" } } }
+				},
+				"name": "getItems",
+				"result_type": { "type": "array", "element_type": { "name": "Item", "type": "named" } }
+			}
+		],
+		"object_types": {
+			"Item": {
+				"fields": {
+					"sku": { "type": { "name": "String", "type": "named" } }
+				}
+			}
+		},
+		"procedures": [],
+		"scalar_types": {
+			"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } }
+		}
+	}`
+
+	var petStore, store NDCRestSchema
+	if err := json.Unmarshal([]byte(rawPetStore), &petStore); err != nil {
+		t.Fatalf("failed to decode schema: %s", err)
+	}
+	if err := json.Unmarshal([]byte(rawStore), &store); err != nil {
+		t.Fatalf("failed to decode schema: %s", err)
+	}
+	sources := []NDCRestSchemaSource{
+		{Name: "petStore", Schema: &petStore},
+		{Name: "store", Schema: &store},
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, err := MergeNDCRestSchemas(sources, MergeConflictError)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, msg := range []string{"store: object type Item conflicts with a previous schema", "store: function getItems conflicts with a previous schema"} {
+			if !strings.Contains(err.Error(), msg) {
+				t.Errorf("expected error with content: %s, got: %s", msg, err)
+			}
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		result, err := MergeNDCRestSchemas(sources, MergeConflictPrefix)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if err := result.Validate(); err != nil {
+			t.Fatalf("expected valid schema, got: %s", err)
+		}
+		assertDeepEqual(t, 2, len(result.Functions))
+		assertDeepEqual(t, 1, len(result.ScalarTypes))
+		assertDeepEqual(t, []string{"Item", "StoreItem"}, getSortedKeys(result.ObjectTypes))
+
+		fn := result.Functions[1]
+		assertDeepEqual(t, "storeGetItems", fn.Name)
+		assertDeepEqual(t, schema.NewArrayType(schema.NewNamedType("StoreItem")).Encode(), fn.ResultType)
+		assertDeepEqual(t, schema.NewNullableNamedType("StoreItem").Encode(), fn.Arguments["filter"].Type)
+		assertDeepEqual(t, "StoreItem", fn.Request.Parameters[0].Schema.Type)
+		assertDeepEqual(t, "https://store.local", fn.Request.Servers[0].URL.String())
+		assertDeepEqual(t, []string{"bearer"}, getSortedKeys(fn.Request.Servers[0].SecuritySchemes))
+
+		petFn := result.Functions[0]
+		assertDeepEqual(t, "getItems", petFn.Name)
+		assertDeepEqual(t, "https://petstore.local", petFn.Request.Servers[0].URL.String())
+		assertDeepEqual(t, AuthSecurities{NewAuthSecurity("api_key", []string{})}, petFn.Request.Security)
+		assertDeepEqual(t, AuthSecurities{NewAuthSecurity("api_key", []string{})}, petFn.Request.Servers[0].Security)
+
+		// the input schemas must not be modified
+		assertDeepEqual(t, "getItems", store.Functions[0].Name)
+		assertDeepEqual(t, 0, len(store.Functions[0].Request.Servers))
+	})
+
+	t.Run("keep_first", func(t *testing.T) {
+		result, err := MergeNDCRestSchemas(sources, MergeConflictKeepFirst)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		assertDeepEqual(t, 1, len(result.Functions))
+		assertDeepEqual(t, []string{"Item"}, getSortedKeys(result.ObjectTypes))
+		assertDeepEqual(t, []string{"id"}, getSortedKeys(result.ObjectTypes["Item"].Fields))
+	})
+}
+
+func TestMergeNDCRestSchemasNestedConflicts(t *testing.T) {
+	newSchema := func(itemField string, functionName string) *NDCRestSchema {
+		var result NDCRestSchema
+		rawSchema := `{
+			"collections": [],
+			"functions": [
+				{
+					"request": { "url": "/orders", "method": "get" },
+					"arguments": {},
+					"name": "` + functionName + `",
+					"result_type": { "name": "Order", "type": "named" }
+				}
+			],
+			"object_types": {
+				"Order": {
+					"fields": {
+						"item": { "type": { "name": "Item", "type": "named" } }
+					}
+				},
+				"Item": {
+					"fields": {
+						"` + itemField + `": { "type": { "name": "String", "type": "named" } }
+					}
+				}
+			},
+			"procedures": [],
+			"scalar_types": {
+				"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } }
+			}
+		}`
+		if err := json.Unmarshal([]byte(rawSchema), &result); err != nil {
+			t.Fatalf("failed to decode schema: %s", err)
+		}
+		return &result
+	}
+
+	sources := []NDCRestSchemaSource{
+		{Name: "petStore", Schema: newSchema("id", "getOrders")},
+		{Name: "store", Schema: newSchema("sku", "getStoreOrders")},
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		result, err := MergeNDCRestSchemas(sources, MergeConflictPrefix)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if err := result.Validate(); err != nil {
+			t.Fatalf("expected valid schema, got: %s", err)
+		}
+		// Order is equal by JSON but refers to the renamed Item type, so it must be renamed too
+		assertDeepEqual(t, []string{"Item", "Order", "StoreItem", "StoreOrder"}, getSortedKeys(result.ObjectTypes))
+		assertDeepEqual(t, schema.NewNamedType("StoreItem").Encode(), result.ObjectTypes["StoreOrder"].Fields["item"].Type)
+		assertDeepEqual(t, schema.NewNamedType("Item").Encode(), result.ObjectTypes["Order"].Fields["item"].Type)
+		assertDeepEqual(t, schema.NewNamedType("StoreOrder").Encode(), result.Functions[1].ResultType)
+	})
+
+	t.Run("keep_first", func(t *testing.T) {
+		_, err := MergeNDCRestSchemas(sources, MergeConflictKeepFirst)
+		expected := "store: function getStoreOrders uses type Order that is replaced by a different type of a previous schema"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error: %s, got: %v", expected, err)
+		}
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		result, err := MergeNDCRestSchemas([]NDCRestSchemaSource{
+			{Name: "petStore", Schema: newSchema("id", "getOrders")},
+			{Name: "store", Schema: newSchema("id", "getStoreOrders")},
+		}, MergeConflictError)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		assertDeepEqual(t, []string{"Item", "Order"}, getSortedKeys(result.ObjectTypes))
+		assertDeepEqual(t, 2, len(result.Functions))
+	})
+}
+
+func TestMergeNDCRestSchemasRenamedRequestTypes(t *testing.T) {
+	newSchema := func(errorField string, prefix string) *NDCRestSchema {
+		var result NDCRestSchema
+		rawSchema := `{
+			"collections": [
+				{
+					"request": {
+						"url": "/errors",
+						"method": "get",
+						"parameters": [{ "name": "filter", "in": "query", "schema": { "type": "Error" } }]
+					},
+					"arguments": {
+						"filter": { "type": { "name": "Error", "type": "named" } }
+					},
+					"name": "` + prefix + `Errors",
+					"type": "Error",
+					"uniqueness_constraints": {},
+					"foreign_keys": {}
+				}
+			],
+			"functions": [],
+			"object_types": {
+				"Error": {
+					"fields": {
+						"` + errorField + `": { "type": { "name": "String", "type": "named" } }
+					}
+				}
+			},
+			"procedures": [],
+			"scalar_types": {
+				"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } }
+			}
+		}`
+		if err := json.Unmarshal([]byte(rawSchema), &result); err != nil {
+			t.Fatalf("failed to decode schema: %s", err)
+		}
+		return &result
+	}
+
+	result, err := MergeNDCRestSchemas([]NDCRestSchemaSource{
+		{Name: "a", Schema: newSchema("message", "a")},
+		{Name: "b", Schema: newSchema("code", "b")},
+	}, MergeConflictPrefix)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	assertDeepEqual(t, []string{"BError", "Error"}, getSortedKeys(result.ObjectTypes))
+
+	// type schemas of collection requests are renamed with arguments
+	collection := result.Collections[1]
+	assertDeepEqual(t, "BError", collection.Type)
+	assertDeepEqual(t, schema.NewNamedType("BError").Encode(), collection.Arguments["filter"].Type)
+	assertDeepEqual(t, "BError", collection.Request.Parameters[0].Schema.Type)
+	assertDeepEqual(t, "Error", result.Collections[0].Request.Parameters[0].Schema.Type)
+}
+
+func TestMergeNDCRestSchemasErrorPayloads(t *testing.T) {
+	newSchema := func(errorField string, functionName string) *NDCRestSchema {
+		var result NDCRestSchema
+		rawSchema := `{
+			"collections": [],
+			"functions": [
+				{
+					"request": {
+						"url": "/pets",
+						"method": "get",
+						"requestBody": {
+							"contentType": "application/json",
+							"schema": {
+								"type": "Result",
+								"discriminator": {
+									"propertyName": "kind",
+									"variants": [{ "value": "error", "type": "Error", "properties": ["` + errorField + `"] }]
+								}
+							}
+						},
+						"response": {
+							"contentType": "application/json",
+							"statusCodes": {
+								"200": { "contentType": "application/json", "schema": { "type": "String" }, "type": { "name": "String", "type": "named" } },
+								"default": { "contentType": "application/json", "schema": { "type": "Error" }, "type": { "name": "Error", "type": "named" } }
+							}
+						}
+					},
+					"arguments": {},
+					"name": "` + functionName + `",
+					"result_type": { "name": "String", "type": "named" }
+				}
+			],
+			"object_types": {
+				"Error": {
+					"fields": {
+						"` + errorField + `": { "type": { "name": "String", "type": "named" } }
+					}
+				}
+			},
+			"procedures": [],
+			"scalar_types": {
+				"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } }
+			}
+		}`
+		if err := json.Unmarshal([]byte(rawSchema), &result); err != nil {
+			t.Fatalf("failed to decode schema: %s", err)
+		}
+		return &result
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		result, err := MergeNDCRestSchemas([]NDCRestSchemaSource{
+			{Name: "petStore", Schema: newSchema("message", "getPets")},
+			{Name: "store", Schema: newSchema("code", "getStorePets")},
+		}, MergeConflictPrefix)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		assertDeepEqual(t, []string{"Error", "StoreError"}, getSortedKeys(result.ObjectTypes))
+
+		// types of error payloads are renamed with the object type
+		errorPayload := result.Functions[1].Request.Response.StatusCodes["default"]
+		assertDeepEqual(t, schema.NewNamedType("StoreError").Encode(), errorPayload.Type)
+		assertDeepEqual(t, "StoreError", errorPayload.Schema.Type)
+		assertDeepEqual(t, schema.NewNamedType("Error").Encode(), result.Functions[0].Request.Response.StatusCodes["default"].Type)
+
+		// types of discriminator variants are renamed
+		assertDeepEqual(t, "StoreError", result.Functions[1].Request.RequestBody.Schema.Discriminator.Variants[0].Type)
+		assertDeepEqual(t, "Error", result.Functions[0].Request.RequestBody.Schema.Discriminator.Variants[0].Type)
+	})
+
+	t.Run("keep_first", func(t *testing.T) {
+		_, err := MergeNDCRestSchemas([]NDCRestSchemaSource{
+			{Name: "petStore", Schema: newSchema("message", "getPets")},
+			{Name: "store", Schema: newSchema("code", "getStorePets")},
+		}, MergeConflictKeepFirst)
+		expected := "store: function getStorePets uses type Error that is replaced by a different type of a previous schema"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error: %s, got: %v", expected, err)
+		}
+	})
+}
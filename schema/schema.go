@@ -2,10 +2,17 @@ package schema
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 
 	"github.com/hasura/ndc-sdk-go/schema"
+	"gopkg.in/yaml.v3"
 )
 
+var urlPathParamRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
 // NDCRestSchema extends the [NDC SchemaResponse] with OpenAPI REST information
 //
 // [NDC schema]: https://github.com/hasura/ndc-sdk-go/blob/1d3339db29e13a170aa8be5ff7fae8394cba0e49/schema/schema.generated.go#L887
@@ -14,7 +21,7 @@ type NDCRestSchema struct {
 	Settings  *NDCRestSettings `json:"settings,omitempty" yaml:"settings,omitempty" mapstructure:"settings"`
 
 	// Collections which are available for queries
-	Collections []schema.CollectionInfo `json:"collections" yaml:"collections" mapstructure:"collections"`
+	Collections []*RESTCollectionInfo `json:"collections" yaml:"collections" mapstructure:"collections"`
 
 	// Functions (i.e. collections which return a single column and row)
 	Functions []*RESTFunctionInfo `json:"functions" yaml:"functions" mapstructure:"functions"`
@@ -35,7 +42,7 @@ func NewNDCRestSchema() *NDCRestSchema {
 	return &NDCRestSchema{
 		SchemaRef:   "https://raw.githubusercontent.com/hasura/ndc-rest-schema/main/jsonschema/ndc-rest-schema.jsonschema",
 		Settings:    &NDCRestSettings{},
-		Collections: []schema.CollectionInfo{},
+		Collections: []*RESTCollectionInfo{},
 		Functions:   []*RESTFunctionInfo{},
 		Procedures:  []*RESTProcedureInfo{},
 		ObjectTypes: make(schema.SchemaResponseObjectTypes),
@@ -45,6 +52,10 @@ func NewNDCRestSchema() *NDCRestSchema {
 
 // ToSchemaResponse converts the instance to NDC schema.SchemaResponse
 func (ndc NDCRestSchema) ToSchemaResponse() *schema.SchemaResponse {
+	collections := make([]schema.CollectionInfo, len(ndc.Collections))
+	for i, collection := range ndc.Collections {
+		collections[i] = collection.CollectionInfo
+	}
 	functions := make([]schema.FunctionInfo, len(ndc.Functions))
 	for i, fn := range ndc.Functions {
 		functions[i] = fn.FunctionInfo
@@ -55,7 +66,7 @@ func (ndc NDCRestSchema) ToSchemaResponse() *schema.SchemaResponse {
 	}
 
 	return &schema.SchemaResponse{
-		Collections: ndc.Collections,
+		Collections: collections,
 		ObjectTypes: ndc.ObjectTypes,
 		ScalarTypes: ndc.ScalarTypes,
 		Functions:   functions,
@@ -63,14 +74,245 @@ func (ndc NDCRestSchema) ToSchemaResponse() *schema.SchemaResponse {
 	}
 }
 
+// Validate checks if the schema is valid.
+// It validates settings and references between operations, types, parameters and security schemes.
+// All problems are collected and returned together
+func (ndc NDCRestSchema) Validate() error {
+	var errs []error
+	var securitySchemes []string
+	if ndc.Settings != nil {
+		if err := ndc.Settings.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("settings: %s", err))
+		}
+		securitySchemes = ndc.Settings.securitySchemeNames()
+		errs = append(errs, validateSecurities(ndc.Settings.Security, securitySchemes, "settings")...)
+	}
+
+	for key, object := range ndc.ObjectTypes {
+		for fieldName, field := range object.Fields {
+			if err := ndc.validateTypeReference(field.Type); err != nil {
+				errs = append(errs, fmt.Errorf("object_types[%s].%s: %s", key, fieldName, err))
+			}
+		}
+	}
+
+	for _, collection := range ndc.Collections {
+		errs = append(errs, ndc.validateCollection(collection, securitySchemes)...)
+	}
+	for _, fn := range ndc.Functions {
+		errs = append(errs, ndc.validateOperation(fmt.Sprintf("function %s", fn.Name), fn.Request, fn.Arguments, fn.ResultType, securitySchemes)...)
+	}
+	for _, proc := range ndc.Procedures {
+		errs = append(errs, ndc.validateOperation(fmt.Sprintf("procedure %s", proc.Name), proc.Request, proc.Arguments, proc.ResultType, securitySchemes)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ndc NDCRestSchema) validateCollection(collection *RESTCollectionInfo, securitySchemes []string) []error {
+	name := fmt.Sprintf("collection %s", collection.Name)
+	var errs []error
+	if _, ok := ndc.ScalarTypes[collection.Type]; ok {
+		errs = append(errs, fmt.Errorf("%s: type %s must be an object type", name, collection.Type))
+	}
+	if collection.Request != nil && collection.Request.Pagination != nil {
+		if err := collection.Request.Pagination.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: pagination: %s", name, err))
+		}
+	}
+	return append(errs, ndc.validateOperation(name, collection.Request, collection.Arguments, schema.NewNamedType(collection.Type).Encode(), securitySchemes)...)
+}
+
+func (ndc NDCRestSchema) validateOperation(name string, request *Request, arguments map[string]schema.ArgumentInfo, resultType schema.Type, securitySchemes []string) []error {
+	var errs []error
+	for key, arg := range arguments {
+		if err := ndc.validateTypeReference(arg.Type); err != nil {
+			errs = append(errs, fmt.Errorf("%s: argument %s: %s", name, key, err))
+		}
+	}
+	if err := ndc.validateTypeReference(resultType); err != nil {
+		errs = append(errs, fmt.Errorf("%s: result type: %s", name, err))
+	}
+
+	if request == nil {
+		return append(errs, fmt.Errorf("%s: request is required", name))
+	}
+	errs = append(errs, ndc.validateResponse(name, request.Response, resultType)...)
+
+	var pathParams []string
+	var paginationParams []string
+	if request.Pagination != nil {
+		paginationParams = request.Pagination.ParameterNames()
+	}
+	for _, param := range request.Parameters {
+		argName := param.ArgumentName
+		if argName == "" {
+			argName = param.Name
+		}
+		// pagination parameters are evaluated from the limit, offset and order_by of the query request
+		if param.In == InQuery && slices.Contains(paginationParams, param.Name) {
+			continue
+		}
+		if _, ok := arguments[argName]; !ok {
+			errs = append(errs, fmt.Errorf("%s: parameter %s does not match any argument", name, argName))
+		}
+		if param.In == InPath {
+			pathParams = append(pathParams, param.Name)
+		}
+	}
+
+	for _, match := range urlPathParamRegex.FindAllStringSubmatch(envVariableRegex.ReplaceAllString(request.URL, ""), -1) {
+		if !slices.Contains(pathParams, match[1]) {
+			errs = append(errs, fmt.Errorf("%s: path parameter %s in url %s is not defined", name, match[1], request.URL))
+		}
+	}
+
+	securitySchemes = slices.Clone(securitySchemes)
+	for _, server := range request.Servers {
+		for key := range server.SecuritySchemes {
+			if !slices.Contains(securitySchemes, key) {
+				securitySchemes = append(securitySchemes, key)
+			}
+		}
+	}
+	return append(errs, validateSecurities(request.Security, securitySchemes, name)...)
+}
+
+// validateResponse checks if mapped response headers match fields of the result object
+func (ndc NDCRestSchema) validateResponse(name string, response Response, resultType schema.Type) []error {
+	var errs []error
+	for _, code := range getSortedKeys(response.StatusCodes) {
+		content := response.StatusCodes[code]
+		if content.Type == nil {
+			continue
+		}
+		if err := ndc.validateTypeReference(content.Type); err != nil {
+			errs = append(errs, fmt.Errorf("%s: response status code %s: %s", name, code, err))
+		}
+	}
+	if len(response.Headers) == 0 && !response.Envelope {
+		return errs
+	}
+	headersObject, err := ndc.getUnderlyingObjectType(resultType)
+	if err != nil {
+		return append(errs, fmt.Errorf("%s: response: %s", name, err))
+	}
+	if response.Envelope {
+		headersField, ok := headersObject.Fields[ResponseEnvelopeHeadersField]
+		if !ok {
+			return append(errs, fmt.Errorf("%s: response: the envelope object must have the %s field", name, ResponseEnvelopeHeadersField))
+		}
+		if _, ok := headersObject.Fields[ResponseEnvelopeBodyField]; !ok {
+			return append(errs, fmt.Errorf("%s: response: the envelope object must have the %s field", name, ResponseEnvelopeBodyField))
+		}
+		headersObject, err = ndc.getUnderlyingObjectType(headersField.Type)
+		if err != nil {
+			return append(errs, fmt.Errorf("%s: response: %s", name, err))
+		}
+	}
+
+	for _, header := range response.Headers {
+		if _, ok := headersObject.Fields[header.FieldName]; !ok {
+			errs = append(errs, fmt.Errorf("%s: response header %s does not match any field of the result object", name, header.Name))
+		}
+	}
+	return errs
+}
+
+// getUnderlyingObjectType gets the object type of the underlying named type
+func (ndc NDCRestSchema) getUnderlyingObjectType(input schema.Type) (*schema.ObjectType, error) {
+	var namedType *schema.NamedType
+	if input != nil {
+		namedType = schema.GetUnderlyingNamedType(input)
+	}
+	if namedType == nil {
+		return nil, errors.New("the result type must be an object type")
+	}
+	object, ok := ndc.ObjectTypes[namedType.Name]
+	if !ok {
+		return nil, fmt.Errorf("the result type %s must be an object type", namedType.Name)
+	}
+	return &object, nil
+}
+
+// validateTypeReference checks if the underlying named type exists in object or scalar types
+func (ndc NDCRestSchema) validateTypeReference(input schema.Type) error {
+	if input == nil {
+		return errors.New("type is required")
+	}
+	namedType := schema.GetUnderlyingNamedType(input)
+	if namedType == nil {
+		return nil
+	}
+	if _, ok := ndc.ObjectTypes[namedType.Name]; ok {
+		return nil
+	}
+	if _, ok := ndc.ScalarTypes[namedType.Name]; ok {
+		return nil
+	}
+	return fmt.Errorf("type %s does not exist", namedType.Name)
+}
+
+func validateSecurities(securities AuthSecurities, securitySchemes []string, name string) []error {
+	var errs []error
+	for _, security := range securities {
+		for key := range security {
+			if !slices.Contains(securitySchemes, key) {
+				errs = append(errs, fmt.Errorf("%s: security scheme %s does not exist", name, key))
+			}
+		}
+	}
+	return errs
+}
+
 type Response struct {
 	ContentType string `json:"contentType" yaml:"contentType" mapstructure:"contentType"`
+	// Response headers that are mapped to fields of the result object.
+	// The result is the headers object if the response has no body, e.g. HEAD requests
+	Headers []ResponseHeader `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
+	// The result is wrapped in an envelope object. The headers field is the object of mapped headers
+	// and the body field is the response body
+	Envelope bool `json:"envelope,omitempty" yaml:"envelope,omitempty" mapstructure:"envelope"`
+	// Documented responses by status code, e.g. 200, 202, 4XX or default
+	StatusCodes map[string]ResponseContent `json:"statusCodes,omitempty" yaml:"statusCodes,omitempty" mapstructure:"statusCodes"`
+}
+
+// ResponseContent represents the content of a documented response status code
+type ResponseContent struct {
+	// The content type of the response body. Empty if the response doesn't have the body
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty" mapstructure:"contentType"`
+	// The schema of the response body
+	Schema *TypeSchema `json:"schema,omitempty" yaml:"schema,omitempty" mapstructure:"schema"`
+	// The NDC type of the response body. Error payloads are decoded to this type
+	Type schema.Type `json:"type,omitempty" yaml:"type,omitempty" mapstructure:"type"`
+	// The field of the union result object that holds the body of the success response.
+	// Only set if success responses have different types
+	FieldName string `json:"fieldName,omitempty" yaml:"fieldName,omitempty" mapstructure:"fieldName"`
+}
+
+const (
+	// The field name of response headers in the envelope object
+	ResponseEnvelopeHeadersField = "headers"
+	// The field name of the response body in the envelope object
+	ResponseEnvelopeBodyField = "body"
+	// The field name of the response status code in the union result object
+	ResponseUnionStatusCodeField = "statusCode"
+)
+
+// ResponseHeader represents a response header that is mapped to a field of the result object
+type ResponseHeader struct {
+	// The name of the HTTP header
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+	// The field name of the result object
+	FieldName string `json:"fieldName" yaml:"fieldName" mapstructure:"fieldName"`
+	// The schema of the header value
+	Schema *TypeSchema `json:"schema,omitempty" yaml:"schema,omitempty" mapstructure:"schema"`
 }
 
 // Request represents the HTTP request information of the webhook
 type Request struct {
 	URL        string               `json:"url,omitempty" yaml:"url,omitempty" mapstructure:"url"`
-	Method     string               `json:"method,omitempty" yaml:"method,omitempty" mapstructure:"method" jsonschema:"enum=get,enum=post,enum=put,enum=patch,enum=delete"`
+	Method     string               `json:"method,omitempty" yaml:"method,omitempty" mapstructure:"method" jsonschema:"enum=get,enum=post,enum=put,enum=patch,enum=delete,enum=head,enum=options,enum=trace"`
 	Type       RequestType          `json:"type,omitempty" yaml:"type,omitempty" mapstructure:"type"`
 	Headers    map[string]EnvString `json:"headers,omitempty" yaml:"headers,omitempty" mapstructure:"headers"`
 	Parameters []RequestParameter   `json:"parameters,omitempty" yaml:"parameters,omitempty" mapstructure:"parameters"`
@@ -81,6 +323,8 @@ type Request struct {
 	RequestBody *RequestBody   `json:"requestBody,omitempty" yaml:"requestBody,omitempty" mapstructure:"requestBody"`
 	Response    Response       `json:"response" yaml:"response" mapstructure:"response"`
 	Retry       *RetryPolicy   `json:"retry,omitempty" yaml:"retry,omitempty" mapstructure:"retry"`
+	// Pagination describes how pagination and sorting of the collection query are mapped to request parameters
+	Pagination *PaginationInfo `json:"pagination,omitempty" yaml:"pagination,omitempty" mapstructure:"pagination"`
 }
 
 // Clone copies this instance to a new one
@@ -97,7 +341,67 @@ func (r Request) Clone() *Request {
 		Servers:     r.Servers,
 		RequestBody: r.RequestBody,
 		Response:    r.Response,
+		Pagination:  r.Pagination,
+	}
+}
+
+// PaginationInfo describes how the limit, offset and order_by of a collection query
+// are mapped to query parameters of a list endpoint
+type PaginationInfo struct {
+	// The pagination strategy of the endpoint
+	Style PaginationStyle `json:"style,omitempty" yaml:"style,omitempty" mapstructure:"style"`
+	// The query parameter of the page size. The query limit is sent with this parameter
+	LimitParameter string `json:"limitParameter,omitempty" yaml:"limitParameter,omitempty" mapstructure:"limitParameter"`
+	// The query parameter of the number of items to be skipped. Required in the offset style
+	OffsetParameter string `json:"offsetParameter,omitempty" yaml:"offsetParameter,omitempty" mapstructure:"offsetParameter"`
+	// The query parameter of the page number. Required in the page style
+	PageParameter string `json:"pageParameter,omitempty" yaml:"pageParameter,omitempty" mapstructure:"pageParameter"`
+	// The number of the first page in the page style. Default 1 if empty
+	FirstPage *int `json:"firstPage,omitempty" yaml:"firstPage,omitempty" mapstructure:"firstPage"`
+	// The query parameter of the cursor. Required in the cursor style
+	CursorParameter string `json:"cursorParameter,omitempty" yaml:"cursorParameter,omitempty" mapstructure:"cursorParameter"`
+	// The dot-separated field path of the next cursor in the response body, e.g. meta.next_cursor.
+	// If empty, the cursor of the next page is the id of the last item
+	NextCursorField string `json:"nextCursorField,omitempty" yaml:"nextCursorField,omitempty" mapstructure:"nextCursorField"`
+	// The field of the response body that contains items. The response body is the item array if empty
+	ItemsField string `json:"itemsField,omitempty" yaml:"itemsField,omitempty" mapstructure:"itemsField"`
+	// The query parameter of sorted fields. Fields in descending order are prefixed with a minus sign, e.g. -created_at,
+	// unless the order direction parameter is set
+	OrderByParameter string `json:"orderByParameter,omitempty" yaml:"orderByParameter,omitempty" mapstructure:"orderByParameter"`
+	// The query parameter of the sort direction, which is asc or desc
+	OrderDirectionParameter string `json:"orderDirectionParameter,omitempty" yaml:"orderDirectionParameter,omitempty" mapstructure:"orderDirectionParameter"`
+}
+
+// Validate checks if the required parameters of the pagination style are set
+func (pi PaginationInfo) Validate() error {
+	switch pi.Style {
+	case PaginationOffset:
+		if pi.OffsetParameter == "" {
+			return errors.New("offsetParameter is required in the offset style")
+		}
+	case PaginationPage:
+		if pi.PageParameter == "" {
+			return errors.New("pageParameter is required in the page style")
+		}
+	case PaginationCursor:
+		if pi.CursorParameter == "" {
+			return errors.New("cursorParameter is required in the cursor style")
+		}
+	default:
+		return fmt.Errorf("invalid PaginationStyle. Expected %+v, got <%s>", paginationStyle_enums, pi.Style)
 	}
+	return nil
+}
+
+// ParameterNames returns names of query parameters that are used for pagination and sorting
+func (pi PaginationInfo) ParameterNames() []string {
+	var results []string
+	for _, name := range []string{pi.LimitParameter, pi.OffsetParameter, pi.PageParameter, pi.CursorParameter, pi.OrderByParameter, pi.OrderDirectionParameter} {
+		if name != "" {
+			results = append(results, name)
+		}
+	}
+	return results
 }
 
 // RequestParameter represents an HTTP request parameter
@@ -113,20 +417,63 @@ type RequestParameter struct {
 // TypeSchema represents a serializable object of OpenAPI schema
 // that is used for validation
 type TypeSchema struct {
-	Type        string                `json:"type" yaml:"type" mapstructure:"type"`
-	Format      string                `json:"format,omitempty" yaml:"format,omitempty" mapstructure:"format"`
-	Pattern     string                `json:"pattern,omitempty" yaml:"pattern,omitempty" mapstructure:"pattern"`
-	Nullable    bool                  `json:"nullable,omitempty" yaml:"nullable,omitempty" mapstructure:"nullable"`
-	Maximum     *float64              `json:"maximum,omitempty" yaml:"maximum,omitempty" mapstructure:"maximum"`
-	Minimum     *float64              `json:"minimum,omitempty," yaml:"minimum,omitempty" mapstructure:"minimum"`
-	MaxLength   *int64                `json:"maxLength,omitempty" yaml:"maxLength,omitempty" mapstructure:"maxLength"`
-	MinLength   *int64                `json:"minLength,omitempty" yaml:"minLength,omitempty" mapstructure:"minLength"`
-	Enum        []string              `json:"enum,omitempty" yaml:"enum,omitempty" mapstructure:"enum"`
-	Items       *TypeSchema           `json:"items,omitempty" yaml:"items,omitempty" mapstructure:"items"`
-	Properties  map[string]TypeSchema `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties"`
-	Description string                `json:"-" yaml:"-"`
-	ReadOnly    bool                  `json:"-" yaml:"-"`
-	WriteOnly   bool                  `json:"-" yaml:"-"`
+	Type             string                `json:"type" yaml:"type" mapstructure:"type"`
+	Format           string                `json:"format,omitempty" yaml:"format,omitempty" mapstructure:"format"`
+	Pattern          string                `json:"pattern,omitempty" yaml:"pattern,omitempty" mapstructure:"pattern"`
+	Nullable         bool                  `json:"nullable,omitempty" yaml:"nullable,omitempty" mapstructure:"nullable"`
+	Maximum          *float64              `json:"maximum,omitempty" yaml:"maximum,omitempty" mapstructure:"maximum"`
+	Minimum          *float64              `json:"minimum,omitempty," yaml:"minimum,omitempty" mapstructure:"minimum"`
+	ExclusiveMaximum *float64              `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty" mapstructure:"exclusiveMaximum"`
+	ExclusiveMinimum *float64              `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty" mapstructure:"exclusiveMinimum"`
+	MultipleOf       *float64              `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty" mapstructure:"multipleOf"`
+	MaxLength        *int64                `json:"maxLength,omitempty" yaml:"maxLength,omitempty" mapstructure:"maxLength"`
+	MinLength        *int64                `json:"minLength,omitempty" yaml:"minLength,omitempty" mapstructure:"minLength"`
+	MaxItems         *int64                `json:"maxItems,omitempty" yaml:"maxItems,omitempty" mapstructure:"maxItems"`
+	MinItems         *int64                `json:"minItems,omitempty" yaml:"minItems,omitempty" mapstructure:"minItems"`
+	UniqueItems      bool                  `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty" mapstructure:"uniqueItems"`
+	MaxProperties    *int64                `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty" mapstructure:"maxProperties"`
+	MinProperties    *int64                `json:"minProperties,omitempty" yaml:"minProperties,omitempty" mapstructure:"minProperties"`
+	Enum             []any                 `json:"enum,omitempty" yaml:"enum,omitempty" mapstructure:"enum"`
+	Const            any                   `json:"const,omitempty" yaml:"const,omitempty" mapstructure:"const"`
+	Default          any                   `json:"default,omitempty" yaml:"default,omitempty" mapstructure:"default"`
+	Items            *TypeSchema           `json:"items,omitempty" yaml:"items,omitempty" mapstructure:"items"`
+	Properties       map[string]TypeSchema `json:"properties,omitempty" yaml:"properties,omitempty" mapstructure:"properties"`
+	Discriminator    *Discriminator        `json:"discriminator,omitempty" yaml:"discriminator,omitempty" mapstructure:"discriminator"`
+	MapEntries       bool                  `json:"mapEntries,omitempty" yaml:"mapEntries,omitempty" mapstructure:"mapEntries"`
+	Representation   NumberRepresentation  `json:"representation,omitempty" yaml:"representation,omitempty" mapstructure:"representation"`
+	Example          any                   `json:"example,omitempty" yaml:"example,omitempty" mapstructure:"example"`
+	Examples         []any                 `json:"examples,omitempty" yaml:"examples,omitempty" mapstructure:"examples"`
+	Description      string                `json:"-" yaml:"-"`
+	ReadOnly         bool                  `json:"-" yaml:"-"`
+	WriteOnly        bool                  `json:"-" yaml:"-"`
+}
+
+// Discriminator represents the variants of a discriminated union that is merged into an object type
+type Discriminator struct {
+	// The name of the property that holds the discriminator value
+	PropertyName string `json:"propertyName" yaml:"propertyName" mapstructure:"propertyName"`
+	// Variants of the union
+	Variants []DiscriminatorVariant `json:"variants" yaml:"variants" mapstructure:"variants"`
+}
+
+// GetVariant finds the variant by the discriminator value
+func (d Discriminator) GetVariant(value string) *DiscriminatorVariant {
+	for i, variant := range d.Variants {
+		if variant.Value == value {
+			return &d.Variants[i]
+		}
+	}
+	return nil
+}
+
+// DiscriminatorVariant represents a variant of the discriminated union
+type DiscriminatorVariant struct {
+	// The discriminator value of the variant
+	Value string `json:"value" yaml:"value" mapstructure:"value"`
+	// The name of the variant object type. Empty if the variant is an inline schema
+	Type string `json:"type,omitempty" yaml:"type,omitempty" mapstructure:"type"`
+	// Properties of the variant. Properties of other variants must be omitted when serializing the request
+	Properties []string `json:"properties" yaml:"properties" mapstructure:"properties"`
 }
 
 // RetryPolicy represents the retry policy of request
@@ -172,6 +519,47 @@ type RequestBody struct {
 	Encoding    map[string]EncodingObject `json:"encoding,omitempty" yaml:"encoding,omitempty" mapstructure:"encoding"`
 }
 
+// RESTCollectionInfo extends NDC collection with OpenAPI REST information
+type RESTCollectionInfo struct {
+	Request               *Request `json:"request" yaml:"request" mapstructure:"request"`
+	schema.CollectionInfo `yaml:",inline"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RESTCollectionInfo) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	rawReq, ok := raw["request"]
+	if ok {
+		var request Request
+		if err := json.Unmarshal(rawReq, &request); err != nil {
+			return err
+		}
+		j.Request = &request
+	}
+
+	var collection schema.CollectionInfo
+	if err := json.Unmarshal(b, &collection); err != nil {
+		return err
+	}
+
+	j.CollectionInfo = collection
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *RESTCollectionInfo) UnmarshalYAML(node *yaml.Node) error {
+	rawBytes, err := yamlNodeToJSON(node)
+	if err != nil {
+		return err
+	}
+
+	return j.UnmarshalJSON(rawBytes)
+}
+
 // RESTFunctionInfo extends NDC query function with OpenAPI REST information
 type RESTFunctionInfo struct {
 	Request             *Request `json:"request" yaml:"request" mapstructure:"request"`
@@ -203,6 +591,16 @@ func (j *RESTFunctionInfo) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *RESTFunctionInfo) UnmarshalYAML(node *yaml.Node) error {
+	rawBytes, err := yamlNodeToJSON(node)
+	if err != nil {
+		return err
+	}
+
+	return j.UnmarshalJSON(rawBytes)
+}
+
 // RESTProcedureInfo extends NDC mutation procedure with OpenAPI REST information
 type RESTProcedureInfo struct {
 	Request              *Request `json:"request" yaml:"request" mapstructure:"request"`
@@ -238,6 +636,36 @@ func toPtr[V any](value V) *V {
 	return &value
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *RESTProcedureInfo) UnmarshalYAML(node *yaml.Node) error {
+	rawBytes, err := yamlNodeToJSON(node)
+	if err != nil {
+		return err
+	}
+
+	return j.UnmarshalJSON(rawBytes)
+}
+
+// yamlNodeToJSON encodes a YAML node to JSON bytes
+// so the value can be decoded with the same rules as JSON
+func yamlNodeToJSON(node *yaml.Node) ([]byte, error) {
+	var raw any
+	if err := node.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(raw)
+}
+
+func getSortedKeys[V any](input map[string]V) []string {
+	results := make([]string, 0, len(input))
+	for key := range input {
+		results = append(results, key)
+	}
+	slices.Sort(results)
+	return results
+}
+
 func toAnySlice[T any](values []T) []any {
 	results := make([]any, len(values))
 	for i, v := range values {
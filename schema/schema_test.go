@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hasura/ndc-sdk-go/schema"
+	"gopkg.in/yaml.v3"
 )
 
 func assertDeepEqual(_ *testing.T, expected any, reality any, msgs ...string) {
@@ -58,6 +59,13 @@ func TestDecodeRESTProcedureInfo(t *testing.T) {
 			}
 			assertDeepEqual(t, tc.expected, procedure)
 			assertDeepEqual(t, tc.expected.Request.Clone(), procedure.Request.Clone())
+
+			var yamlProcedure RESTProcedureInfo
+			if err := yaml.Unmarshal([]byte(tc.raw), &yamlProcedure); err != nil {
+				t.Errorf("failed to unmarshal yaml: %s", err)
+				t.FailNow()
+			}
+			assertDeepEqual(t, tc.expected, yamlProcedure)
 		})
 	}
 }
@@ -140,6 +148,251 @@ func TestDecodeRESTFunctionInfo(t *testing.T) {
 			assertDeepEqual(t, tc.expected, fn)
 			assertDeepEqual(t, tc.expected.Request.Clone(), fn.Request.Clone())
 
+			var yamlFn RESTFunctionInfo
+			if err := yaml.Unmarshal([]byte(tc.raw), &yamlFn); err != nil {
+				t.Errorf("failed to unmarshal yaml: %s", err)
+				t.FailNow()
+			}
+			assertDeepEqual(t, tc.expected, yamlFn)
+		})
+	}
+}
+
+func TestValidateNDCRestSchema(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		errorMsg []string
+	}{
+		{
+			name: "success",
+			raw: `{
+				"settings": {
+					"servers": [{ "url": "https://example.local" }],
+					"securitySchemes": {
+						"api_key": { "type": "apiKey", "value": "{{API_KEY}}", "in": "header", "name": "api_key" }
+					},
+					"security": [{ "api_key": [] }]
+				},
+				"collections": [
+					{
+						"request": {
+							"url": "/pets",
+							"method": "get",
+							"parameters": [
+								{ "name": "limit", "in": "query", "schema": { "type": "Int64" } },
+								{ "name": "offset", "in": "query", "schema": { "type": "Int64" } }
+							],
+							"pagination": { "style": "offset", "limitParameter": "limit", "offsetParameter": "offset" }
+						},
+						"arguments": {},
+						"foreign_keys": {},
+						"name": "pets",
+						"type": "Pet",
+						"uniqueness_constraints": {}
+					}
+				],
+				"functions": [
+					{
+						"request": {
+							"url": "/pets/{petId}",
+							"method": "get",
+							"parameters": [{ "name": "petId", "in": "path", "schema": { "type": "Int64" } }],
+							"security": [{}, { "api_key": [] }]
+						},
+						"arguments": {
+							"petId": { "type": { "name": "Int64", "type": "named" } }
+						},
+						"name": "getPet",
+						"result_type": { "name": "Pet", "type": "named" }
+					}
+				],
+				"object_types": {
+					"Pet": {
+						"fields": {
+							"id": { "type": { "name": "Int64", "type": "named" } }
+						}
+					}
+				},
+				"procedures": [],
+				"scalar_types": {
+					"Int64": {
+						"aggregate_functions": {},
+						"comparison_operators": {},
+						"representation": { "type": "int64" }
+					}
+				}
+			}`,
+		},
+		{
+			name: "invalid_references",
+			raw: `{
+				"settings": {
+					"servers": [{ "url": "https://example.local" }],
+					"security": [{ "api_key": [] }]
+				},
+				"collections": [
+					{
+						"request": {
+							"url": "/pets",
+							"method": "get",
+							"pagination": { "style": "page", "limitParameter": "limit" }
+						},
+						"arguments": {},
+						"foreign_keys": {},
+						"name": "pets",
+						"type": "Pet",
+						"uniqueness_constraints": {}
+					}
+				],
+				"functions": [],
+				"object_types": {
+					"Pet": {
+						"fields": {
+							"category": { "type": { "type": "nullable", "underlying_type": { "name": "Category", "type": "named" } } }
+						}
+					}
+				},
+				"procedures": [
+					{
+						"request": {
+							"url": "/pets/{petId}",
+							"method": "put",
+							"parameters": [{ "name": "id", "in": "query" }],
+							"security": [{ "petstore_auth": [] }]
+						},
+						"arguments": {
+							"body": { "type": { "name": "PetInput", "type": "named" } }
+						},
+						"name": "updatePet",
+						"result_type": { "type": "array", "element_type": { "name": "Pet", "type": "named" } }
+					}
+				],
+				"scalar_types": {}
+			}`,
+			errorMsg: []string{
+				"settings: security scheme api_key does not exist",
+				"collection pets: pagination: pageParameter is required in the page style",
+				"object_types[Pet].category: type Category does not exist",
+				"procedure updatePet: argument body: type PetInput does not exist",
+				"procedure updatePet: parameter id does not match any argument",
+				"procedure updatePet: path parameter petId in url /pets/{petId} is not defined",
+				"procedure updatePet: security scheme petstore_auth does not exist",
+			},
+		},
+		{
+			name: "invalid_response_headers",
+			raw: `{
+				"functions": [
+					{
+						"request": {
+							"url": "/pets",
+							"method": "head",
+							"response": {
+								"contentType": "application/json",
+								"headers": [{ "name": "X-Total-Count", "fieldName": "xTotalCount" }]
+							}
+						},
+						"arguments": {},
+						"name": "headPets",
+						"result_type": { "name": "HeadPetsResult", "type": "named" }
+					}
+				],
+				"object_types": {
+					"HeadPetsResult": {
+						"fields": {
+							"eTag": { "type": { "name": "String", "type": "named" } }
+						}
+					}
+				},
+				"procedures": [
+					{
+						"request": {
+							"url": "/pets",
+							"method": "post",
+							"response": {
+								"contentType": "application/json",
+								"headers": [{ "name": "Location", "fieldName": "location" }],
+								"envelope": true
+							}
+						},
+						"arguments": {},
+						"name": "addPet",
+						"result_type": { "name": "HeadPetsResult", "type": "named" }
+					}
+				],
+				"scalar_types": {
+					"String": {
+						"aggregate_functions": {},
+						"comparison_operators": {},
+						"representation": { "type": "string" }
+					}
+				}
+			}`,
+			errorMsg: []string{
+				"function headPets: response header X-Total-Count does not match any field of the result object",
+				"procedure addPet: response: the envelope object must have the headers field",
+			},
+		},
+		{
+			name: "invalid_status_codes",
+			raw: `{
+				"functions": [
+					{
+						"request": {
+							"url": "/pets",
+							"method": "get",
+							"response": {
+								"contentType": "application/json",
+								"statusCodes": {
+									"200": { "type": { "name": "String", "type": "named" } },
+									"404": { "type": { "name": "NotFoundError", "type": "named" } },
+									"default": {}
+								}
+							}
+						},
+						"arguments": {},
+						"name": "listPets",
+						"result_type": { "name": "String", "type": "named" }
+					}
+				],
+				"object_types": {},
+				"procedures": [],
+				"scalar_types": {
+					"String": {
+						"aggregate_functions": {},
+						"comparison_operators": {},
+						"representation": { "type": "string" }
+					}
+				}
+			}`,
+			errorMsg: []string{
+				"function listPets: response status code 404: type NotFoundError does not exist",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ndcSchema NDCRestSchema
+			if err := json.Unmarshal([]byte(tc.raw), &ndcSchema); err != nil {
+				t.Fatalf("failed to unmarshal: %s", err)
+			}
+			err := ndcSchema.Validate()
+			if len(tc.errorMsg) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			for _, msg := range tc.errorMsg {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("expected error with content: %s, got: %s", msg, err)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,452 @@
+// Package serialize encodes argument values of request parameters following the [parameter serialization] rules of OpenAPI.
+//
+// [parameter serialization]: https://spec.openapis.org/oas/v3.1.0#style-examples
+package serialize
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+)
+
+// Pair represents an encoded name and value of a query parameter or cookie
+type Pair struct {
+	Name  string
+	Value string
+}
+
+// String returns the pair in the name=value form
+func (p Pair) String() string {
+	return fmt.Sprintf("%s=%s", p.Name, p.Value)
+}
+
+// JoinQuery joins encoded query pairs into a query string, e.g. id=1&id=2
+func JoinQuery(pairs []Pair) string {
+	return joinPairs(pairs, "&")
+}
+
+// JoinCookies joins encoded cookie pairs into the value of the Cookie header, e.g. id=1; name=foo
+func JoinCookies(pairs []Pair) string {
+	return joinPairs(pairs, "; ")
+}
+
+// EncodePathParameter encodes the value of a path parameter with the simple, label or matrix style.
+// The result replaces the {name} template of the URL path
+func EncodePathParameter(param rest.RequestParameter, value any) (string, error) {
+	style, explode, err := getEncodingStyle(param)
+	if err != nil {
+		return "", err
+	}
+	if isNil(value) {
+		return "", fmt.Errorf("path parameter %s: value is required", param.Name)
+	}
+	escape := func(s string) string {
+		return escapeString(s, false)
+	}
+	items, err := decodeValue(value, escape)
+	if err != nil {
+		return "", fmt.Errorf("path parameter %s: %w", param.Name, err)
+	}
+
+	switch style {
+	case rest.EncodingStyleLabel:
+		if explode {
+			return "." + items.join(true, "."), nil
+		}
+		return "." + items.join(false, ","), nil
+	case rest.EncodingStyleMatrix:
+		prefix := ";" + param.Name
+		switch {
+		case explode && items.kind == kindArray && len(items.values) > 0:
+			return prefix + "=" + strings.Join(items.values, prefix+"="), nil
+		case explode && items.kind == kindObject && len(items.pairs) > 0:
+			return ";" + items.join(true, ";"), nil
+		}
+		if result := items.join(false, ","); result != "" {
+			return prefix + "=" + result, nil
+		}
+		return prefix, nil
+	default:
+		return items.join(explode, ","), nil
+	}
+}
+
+// EncodeQueryParameter encodes the value of a query parameter with the form, spaceDelimited, pipeDelimited or deepObject style.
+// A nil value is omitted
+func EncodeQueryParameter(param rest.RequestParameter, value any) ([]Pair, error) {
+	if param.In != rest.InQuery {
+		return nil, fmt.Errorf("parameter %s: unsupported location %s", param.Name, param.In)
+	}
+	encoding := param.EncodingObject
+	if encoding.Style == "" {
+		encoding.Style = rest.EncodingStyleForm
+	}
+	if !slices.Contains([]rest.ParameterEncodingStyle{rest.EncodingStyleForm, rest.EncodingStyleSpaceDelimited, rest.EncodingStylePipeDelimited, rest.EncodingStyleDeepObject}, encoding.Style) {
+		return nil, fmt.Errorf("query parameter %s: style %s is not supported", param.Name, encoding.Style)
+	}
+	results, err := EncodeQuery(param.Name, encoding, value)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %s: %w", param.Name, err)
+	}
+	return results, nil
+}
+
+// EncodeQuery encodes the value of a query parameter or a field of the application/x-www-form-urlencoded body
+// with the encoding object. The style is form if empty
+func EncodeQuery(name string, encoding rest.EncodingObject, value any) ([]Pair, error) {
+	if isNil(value) {
+		return nil, nil
+	}
+	style := encoding.Style
+	if style == "" {
+		style = rest.EncodingStyleForm
+	}
+	explode := style == rest.EncodingStyleForm
+	if encoding.Explode != nil {
+		explode = *encoding.Explode
+	}
+	escape := func(s string) string {
+		return escapeString(s, encoding.AllowReserved)
+	}
+	encodedName := escapeString(name, false)
+
+	if style == rest.EncodingStyleDeepObject {
+		var results []Pair
+		if err := encodeDeepObject(&results, encodedName, reflect.ValueOf(value), escape); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	items, err := decodeValue(value, escape)
+	if err != nil {
+		return nil, err
+	}
+	if items.kind == kindPrimitive {
+		return []Pair{{Name: encodedName, Value: items.value}}, nil
+	}
+	if explode {
+		if items.kind == kindArray {
+			results := make([]Pair, len(items.values))
+			for i, item := range items.values {
+				results[i] = Pair{Name: encodedName, Value: item}
+			}
+			return results, nil
+		}
+		return items.pairs, nil
+	}
+
+	separator := ","
+	switch style {
+	case rest.EncodingStyleSpaceDelimited:
+		separator = "%20"
+	case rest.EncodingStylePipeDelimited:
+		separator = "|"
+	}
+	return []Pair{{Name: encodedName, Value: items.join(false, separator)}}, nil
+}
+
+// EncodeHeaderParameter encodes the value of a header parameter with the simple style. A nil value returns an empty string
+func EncodeHeaderParameter(param rest.RequestParameter, value any) (string, error) {
+	_, explode, err := getEncodingStyle(param)
+	if err != nil {
+		return "", err
+	}
+	if isNil(value) {
+		return "", nil
+	}
+	items, err := decodeValue(value, func(s string) string {
+		return s
+	})
+	if err != nil {
+		return "", fmt.Errorf("header parameter %s: %w", param.Name, err)
+	}
+	return items.join(explode, ","), nil
+}
+
+// EncodeCookieParameter encodes the value of a cookie parameter with the form style. A nil value is omitted
+func EncodeCookieParameter(param rest.RequestParameter, value any) ([]Pair, error) {
+	_, explode, err := getEncodingStyle(param)
+	if err != nil {
+		return nil, err
+	}
+	if isNil(value) {
+		return nil, nil
+	}
+	escape := func(s string) string {
+		return escapeString(s, false)
+	}
+	items, err := decodeValue(value, escape)
+	if err != nil {
+		return nil, fmt.Errorf("cookie parameter %s: %w", param.Name, err)
+	}
+	name := escape(param.Name)
+	switch {
+	case items.kind == kindPrimitive:
+		return []Pair{{Name: name, Value: items.value}}, nil
+	case !explode:
+		return []Pair{{Name: name, Value: items.join(false, ",")}}, nil
+	case items.kind == kindArray:
+		results := make([]Pair, len(items.values))
+		for i, item := range items.values {
+			results[i] = Pair{Name: name, Value: item}
+		}
+		return results, nil
+	default:
+		return items.pairs, nil
+	}
+}
+
+// getEncodingStyle returns the style and explode of a path, header or cookie parameter, or the default values of its location
+func getEncodingStyle(param rest.RequestParameter) (rest.ParameterEncodingStyle, bool, error) {
+	var supportedStyles []rest.ParameterEncodingStyle
+	switch param.In {
+	case rest.InPath:
+		supportedStyles = []rest.ParameterEncodingStyle{rest.EncodingStyleSimple, rest.EncodingStyleLabel, rest.EncodingStyleMatrix}
+	case rest.InHeader:
+		supportedStyles = []rest.ParameterEncodingStyle{rest.EncodingStyleSimple}
+	case rest.InCookie:
+		supportedStyles = []rest.ParameterEncodingStyle{rest.EncodingStyleForm}
+	default:
+		return "", false, fmt.Errorf("parameter %s: unsupported location %s", param.Name, param.In)
+	}
+
+	style := param.Style
+	if style == "" {
+		style = supportedStyles[0]
+	}
+	if !slices.Contains(supportedStyles, style) {
+		return "", false, fmt.Errorf("%s parameter %s: style %s is not supported", param.In, param.Name, style)
+	}
+	explode := style == rest.EncodingStyleForm
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return style, explode, nil
+}
+
+// encodeDeepObject encodes nested objects and arrays to pairs in the name[key][index]=value form
+func encodeDeepObject(results *[]Pair, name string, value reflect.Value, escape func(string) string) error {
+	value, ok := indirect(value)
+	if !ok {
+		return nil
+	}
+	if primitive, ok := formatPrimitive(value); ok {
+		*results = append(*results, Pair{Name: name, Value: escape(primitive)})
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := encodeDeepObject(results, fmt.Sprintf("%s[%d]", name, i), value.Index(i), escape); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		for _, key := range getSortedMapKeys(value) {
+			if err := encodeDeepObject(results, fmt.Sprintf("%s[%s]", name, escapeString(key.String(), false)), value.MapIndex(key.value), escape); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported value type %s", value.Type())
+	}
+}
+
+type valueKind int
+
+const (
+	kindPrimitive valueKind = iota
+	kindArray
+	kindObject
+)
+
+// decodedValue holds escaped values of a primitive, an array or an object
+type decodedValue struct {
+	kind   valueKind
+	value  string
+	values []string
+	pairs  []Pair
+}
+
+// join joins array items or object properties with the separator.
+// Properties are joined as key=value pairs if explode is true, or flattened to key and value items otherwise
+func (dv decodedValue) join(explode bool, separator string) string {
+	switch dv.kind {
+	case kindArray:
+		return strings.Join(dv.values, separator)
+	case kindObject:
+		if explode {
+			return joinPairs(dv.pairs, separator)
+		}
+		values := make([]string, 0, len(dv.pairs)*2)
+		for _, pair := range dv.pairs {
+			values = append(values, pair.Name, pair.Value)
+		}
+		return strings.Join(values, separator)
+	default:
+		return dv.value
+	}
+}
+
+// decodeValue decodes the value to escaped strings. Nested arrays and objects are encoded as JSON strings
+func decodeValue(value any, escape func(string) string) (*decodedValue, error) {
+	reflectValue, ok := indirect(reflect.ValueOf(value))
+	if !ok {
+		return &decodedValue{kind: kindPrimitive}, nil
+	}
+	if primitive, ok := formatPrimitive(reflectValue); ok {
+		return &decodedValue{kind: kindPrimitive, value: escape(primitive)}, nil
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		result := &decodedValue{
+			kind:   kindArray,
+			values: make([]string, reflectValue.Len()),
+		}
+		for i := range result.values {
+			item, err := formatItem(reflectValue.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			result.values[i] = escape(item)
+		}
+		return result, nil
+	case reflect.Map:
+		result := &decodedValue{kind: kindObject}
+		for _, key := range getSortedMapKeys(reflectValue) {
+			item := reflectValue.MapIndex(key.value)
+			if _, ok := indirect(item); !ok {
+				continue
+			}
+			str, err := formatItem(item)
+			if err != nil {
+				return nil, err
+			}
+			result.pairs = append(result.pairs, Pair{Name: escape(key.String()), Value: escape(str)})
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", reflectValue.Type())
+	}
+}
+
+// formatItem formats an array item or object property. Nested arrays and objects are encoded as JSON strings
+func formatItem(value reflect.Value) (string, error) {
+	value, ok := indirect(value)
+	if !ok {
+		return "", nil
+	}
+	if primitive, ok := formatPrimitive(value); ok {
+		return primitive, nil
+	}
+	bs, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+func formatPrimitive(value reflect.Value) (string, bool) {
+	if value.CanInterface() {
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+			bs, err := marshaler.MarshalText()
+			if err == nil {
+				return string(bs), true
+			}
+		}
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+type mapKey struct {
+	value reflect.Value
+	name  string
+}
+
+func (mk mapKey) String() string {
+	return mk.name
+}
+
+func getSortedMapKeys(value reflect.Value) []mapKey {
+	keys := make([]mapKey, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		name, ok := formatPrimitive(key)
+		if !ok {
+			name = fmt.Sprint(key.Interface())
+		}
+		keys = append(keys, mapKey{value: key, name: name})
+	}
+	slices.SortFunc(keys, func(a, b mapKey) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return keys
+}
+
+// indirect dereferences pointers and interfaces. It returns false if the value is nil
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, value.IsValid()
+}
+
+func isNil(value any) bool {
+	_, ok := indirect(reflect.ValueOf(value))
+	return !ok
+}
+
+func joinPairs(pairs []Pair, separator string) string {
+	values := make([]string, len(pairs))
+	for i, pair := range pairs {
+		values[i] = pair.String()
+	}
+	return strings.Join(values, separator)
+}
+
+// escapeString percent-encodes characters except unreserved characters of RFC 3986.
+// Reserved characters :/?#[]@!$&'()*+,;= are kept if allowReserved is true
+func escapeString(value string, allowReserved bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isUnreserved(c) || (allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0) {
+			builder.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", c)
+	}
+	return builder.String()
+}
+
+func isUnreserved(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~'
+}
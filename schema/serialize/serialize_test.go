@@ -0,0 +1,309 @@
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+)
+
+var (
+	colorPrimitive = "blue"
+	colorArray     = []any{"blue", "black", "brown"}
+	colorObject    = map[string]any{"R": 100, "G": 200, "B": 150}
+)
+
+func newParameter(in rest.ParameterLocation, style rest.ParameterEncodingStyle, explode *bool) rest.RequestParameter {
+	return rest.RequestParameter{
+		Name: "color",
+		In:   in,
+		EncodingObject: rest.EncodingObject{
+			Style:   style,
+			Explode: explode,
+		},
+	}
+}
+
+func TestEncodePathParameter(t *testing.T) {
+	explode, noExplode := toPtr(true), toPtr(false)
+	testCases := []struct {
+		Style    rest.ParameterEncodingStyle
+		Explode  *bool
+		Value    any
+		Expected string
+	}{
+		// simple
+		{Style: rest.EncodingStyleSimple, Explode: noExplode, Value: "", Expected: ""},
+		{Style: rest.EncodingStyleSimple, Explode: noExplode, Value: colorPrimitive, Expected: "blue"},
+		{Style: rest.EncodingStyleSimple, Explode: noExplode, Value: colorArray, Expected: "blue,black,brown"},
+		{Style: rest.EncodingStyleSimple, Explode: noExplode, Value: colorObject, Expected: "B,150,G,200,R,100"},
+		{Style: rest.EncodingStyleSimple, Explode: explode, Value: "", Expected: ""},
+		{Style: rest.EncodingStyleSimple, Explode: explode, Value: colorPrimitive, Expected: "blue"},
+		{Style: rest.EncodingStyleSimple, Explode: explode, Value: colorArray, Expected: "blue,black,brown"},
+		{Style: rest.EncodingStyleSimple, Explode: explode, Value: colorObject, Expected: "B=150,G=200,R=100"},
+		{Style: "", Value: colorObject, Expected: "B,150,G,200,R,100"},
+		// label
+		{Style: rest.EncodingStyleLabel, Explode: noExplode, Value: "", Expected: "."},
+		{Style: rest.EncodingStyleLabel, Explode: noExplode, Value: colorPrimitive, Expected: ".blue"},
+		{Style: rest.EncodingStyleLabel, Explode: noExplode, Value: colorArray, Expected: ".blue,black,brown"},
+		{Style: rest.EncodingStyleLabel, Explode: noExplode, Value: colorObject, Expected: ".B,150,G,200,R,100"},
+		{Style: rest.EncodingStyleLabel, Explode: explode, Value: "", Expected: "."},
+		{Style: rest.EncodingStyleLabel, Explode: explode, Value: colorPrimitive, Expected: ".blue"},
+		{Style: rest.EncodingStyleLabel, Explode: explode, Value: colorArray, Expected: ".blue.black.brown"},
+		{Style: rest.EncodingStyleLabel, Explode: explode, Value: colorObject, Expected: ".B=150.G=200.R=100"},
+		// matrix
+		{Style: rest.EncodingStyleMatrix, Explode: noExplode, Value: "", Expected: ";color"},
+		{Style: rest.EncodingStyleMatrix, Explode: noExplode, Value: colorPrimitive, Expected: ";color=blue"},
+		{Style: rest.EncodingStyleMatrix, Explode: noExplode, Value: colorArray, Expected: ";color=blue,black,brown"},
+		{Style: rest.EncodingStyleMatrix, Explode: noExplode, Value: colorObject, Expected: ";color=B,150,G,200,R,100"},
+		{Style: rest.EncodingStyleMatrix, Explode: explode, Value: "", Expected: ";color"},
+		{Style: rest.EncodingStyleMatrix, Explode: explode, Value: colorPrimitive, Expected: ";color=blue"},
+		{Style: rest.EncodingStyleMatrix, Explode: explode, Value: colorArray, Expected: ";color=blue;color=black;color=brown"},
+		{Style: rest.EncodingStyleMatrix, Explode: explode, Value: colorObject, Expected: ";B=150;G=200;R=100"},
+		{Style: rest.EncodingStyleMatrix, Explode: explode, Value: []any{}, Expected: ";color"},
+		// escape
+		{Style: rest.EncodingStyleSimple, Value: "a/b c,d", Expected: "a%2Fb%20c%2Cd"},
+		{Style: rest.EncodingStyleSimple, Value: []any{1, 2.5, true, nil}, Expected: "1,2.5,true,"},
+		{Style: rest.EncodingStyleSimple, Value: []any{map[string]any{"id": 1}}, Expected: "%7B%22id%22%3A1%7D"},
+	}
+
+	for _, tc := range testCases {
+		param := newParameter(rest.InPath, tc.Style, tc.Explode)
+		t.Run(getTestName(param, tc.Value), func(t *testing.T) {
+			result, err := EncodePathParameter(param, tc.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tc.Expected {
+				t.Errorf("expected: %s, got: %s", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestEncodeQueryParameter(t *testing.T) {
+	explode, noExplode := toPtr(true), toPtr(false)
+	testCases := []struct {
+		Style         rest.ParameterEncodingStyle
+		Explode       *bool
+		AllowReserved bool
+		Value         any
+		Expected      string
+	}{
+		// form
+		{Style: rest.EncodingStyleForm, Explode: noExplode, Value: "", Expected: "color="},
+		{Style: rest.EncodingStyleForm, Explode: noExplode, Value: colorPrimitive, Expected: "color=blue"},
+		{Style: rest.EncodingStyleForm, Explode: noExplode, Value: colorArray, Expected: "color=blue,black,brown"},
+		{Style: rest.EncodingStyleForm, Explode: noExplode, Value: colorObject, Expected: "color=B,150,G,200,R,100"},
+		{Style: rest.EncodingStyleForm, Explode: explode, Value: "", Expected: "color="},
+		{Style: rest.EncodingStyleForm, Explode: explode, Value: colorPrimitive, Expected: "color=blue"},
+		{Style: rest.EncodingStyleForm, Explode: explode, Value: colorArray, Expected: "color=blue&color=black&color=brown"},
+		{Style: rest.EncodingStyleForm, Explode: explode, Value: colorObject, Expected: "B=150&G=200&R=100"},
+		{Style: "", Value: colorArray, Expected: "color=blue&color=black&color=brown"},
+		{Style: "", Value: nil, Expected: ""},
+		// spaceDelimited
+		{Style: rest.EncodingStyleSpaceDelimited, Explode: noExplode, Value: colorArray, Expected: "color=blue%20black%20brown"},
+		{Style: rest.EncodingStyleSpaceDelimited, Explode: noExplode, Value: colorObject, Expected: "color=B%20150%20G%20200%20R%20100"},
+		{Style: rest.EncodingStyleSpaceDelimited, Explode: explode, Value: colorArray, Expected: "color=blue&color=black&color=brown"},
+		{Style: rest.EncodingStyleSpaceDelimited, Explode: explode, Value: colorObject, Expected: "B=150&G=200&R=100"},
+		// pipeDelimited
+		{Style: rest.EncodingStylePipeDelimited, Explode: noExplode, Value: colorArray, Expected: "color=blue|black|brown"},
+		{Style: rest.EncodingStylePipeDelimited, Explode: noExplode, Value: colorObject, Expected: "color=B|150|G|200|R|100"},
+		{Style: rest.EncodingStylePipeDelimited, Explode: explode, Value: colorArray, Expected: "color=blue&color=black&color=brown"},
+		{Style: rest.EncodingStylePipeDelimited, Explode: explode, Value: colorObject, Expected: "B=150&G=200&R=100"},
+		// deepObject
+		{Style: rest.EncodingStyleDeepObject, Explode: explode, Value: colorObject, Expected: "color[B]=150&color[G]=200&color[R]=100"},
+		{Style: rest.EncodingStyleDeepObject, Explode: noExplode, Value: colorObject, Expected: "color[B]=150&color[G]=200&color[R]=100"},
+		{Style: rest.EncodingStyleDeepObject, Explode: explode, Value: colorPrimitive, Expected: "color=blue"},
+		{
+			Style:    rest.EncodingStyleDeepObject,
+			Explode:  explode,
+			Value:    map[string]any{"filter": map[string]any{"name": "a b", "ids": []int{1, 2}}, "empty": nil},
+			Expected: "color[filter][ids][0]=1&color[filter][ids][1]=2&color[filter][name]=a%20b",
+		},
+		{
+			Style:    rest.EncodingStyleDeepObject,
+			Explode:  explode,
+			Value:    []any{map[string]any{"id": 1}, map[string]any{"id": 2, "tags": []string{"x"}}},
+			Expected: "color[0][id]=1&color[1][id]=2&color[1][tags][0]=x",
+		},
+		// arrays of objects in other styles are encoded as JSON strings
+		{Style: rest.EncodingStyleForm, Explode: explode, Value: []any{map[string]any{"id": 1}}, Expected: "color=%7B%22id%22%3A1%7D"},
+		// allowReserved
+		{Style: rest.EncodingStyleForm, Value: "a/b?c", Expected: "color=a%2Fb%3Fc"},
+		{Style: rest.EncodingStyleForm, AllowReserved: true, Value: "a/b?c", Expected: "color=a/b?c"},
+	}
+
+	for _, tc := range testCases {
+		param := newParameter(rest.InQuery, tc.Style, tc.Explode)
+		param.AllowReserved = tc.AllowReserved
+		t.Run(getTestName(param, tc.Value), func(t *testing.T) {
+			result, err := EncodeQueryParameter(param, tc.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query := JoinQuery(result); query != tc.Expected {
+				t.Errorf("expected: %s, got: %s", tc.Expected, query)
+			}
+		})
+	}
+}
+
+func TestEncodeHeaderParameter(t *testing.T) {
+	explode, noExplode := toPtr(true), toPtr(false)
+	testCases := []struct {
+		Explode  *bool
+		Value    any
+		Expected string
+	}{
+		{Explode: noExplode, Value: colorPrimitive, Expected: "blue"},
+		{Explode: noExplode, Value: colorArray, Expected: "blue,black,brown"},
+		{Explode: noExplode, Value: colorObject, Expected: "B,150,G,200,R,100"},
+		{Explode: explode, Value: colorPrimitive, Expected: "blue"},
+		{Explode: explode, Value: colorArray, Expected: "blue,black,brown"},
+		{Explode: explode, Value: colorObject, Expected: "B=150,G=200,R=100"},
+		{Value: "a b/c", Expected: "a b/c"},
+		{Value: nil, Expected: ""},
+	}
+
+	for _, tc := range testCases {
+		param := newParameter(rest.InHeader, "", tc.Explode)
+		t.Run(getTestName(param, tc.Value), func(t *testing.T) {
+			result, err := EncodeHeaderParameter(param, tc.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tc.Expected {
+				t.Errorf("expected: %s, got: %s", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestEncodeCookieParameter(t *testing.T) {
+	explode, noExplode := toPtr(true), toPtr(false)
+	testCases := []struct {
+		Explode  *bool
+		Value    any
+		Expected string
+	}{
+		{Explode: noExplode, Value: colorPrimitive, Expected: "color=blue"},
+		{Explode: noExplode, Value: colorArray, Expected: "color=blue,black,brown"},
+		{Explode: noExplode, Value: colorObject, Expected: "color=B,150,G,200,R,100"},
+		{Explode: explode, Value: colorPrimitive, Expected: "color=blue"},
+		{Explode: explode, Value: colorArray, Expected: "color=blue; color=black; color=brown"},
+		{Explode: explode, Value: colorObject, Expected: "B=150; G=200; R=100"},
+		{Value: "a;b", Expected: "color=a%3Bb"},
+		{Value: nil, Expected: ""},
+	}
+
+	for _, tc := range testCases {
+		param := newParameter(rest.InCookie, "", tc.Explode)
+		t.Run(getTestName(param, tc.Value), func(t *testing.T) {
+			result, err := EncodeCookieParameter(param, tc.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cookie := JoinCookies(result); cookie != tc.Expected {
+				t.Errorf("expected: %s, got: %s", tc.Expected, cookie)
+			}
+		})
+	}
+}
+
+func TestEncodeParameterErrors(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Encode   func() error
+		Expected string
+	}{
+		{
+			Name: "path_required",
+			Encode: func() error {
+				_, err := EncodePathParameter(newParameter(rest.InPath, "", nil), nil)
+				return err
+			},
+			Expected: "path parameter color: value is required",
+		},
+		{
+			Name: "path_style",
+			Encode: func() error {
+				_, err := EncodePathParameter(newParameter(rest.InPath, rest.EncodingStyleForm, nil), colorPrimitive)
+				return err
+			},
+			Expected: "path parameter color: style form is not supported",
+		},
+		{
+			Name: "query_style",
+			Encode: func() error {
+				_, err := EncodeQueryParameter(newParameter(rest.InQuery, rest.EncodingStyleMatrix, nil), colorPrimitive)
+				return err
+			},
+			Expected: "query parameter color: style matrix is not supported",
+		},
+		{
+			Name: "header_style",
+			Encode: func() error {
+				_, err := EncodeHeaderParameter(newParameter(rest.InHeader, rest.EncodingStyleLabel, nil), colorPrimitive)
+				return err
+			},
+			Expected: "header parameter color: style label is not supported",
+		},
+		{
+			Name: "location",
+			Encode: func() error {
+				_, err := EncodeCookieParameter(newParameter(rest.InQuery, "", nil), colorPrimitive)
+				return err
+			},
+			Expected: "parameter color: unsupported location query",
+		},
+		{
+			Name: "query_location",
+			Encode: func() error {
+				_, err := EncodeQueryParameter(newParameter(rest.InHeader, "", nil), colorPrimitive)
+				return err
+			},
+			Expected: "parameter color: unsupported location header",
+		},
+		{
+			Name: "unsupported_type",
+			Encode: func() error {
+				_, err := EncodeHeaderParameter(newParameter(rest.InHeader, "", nil), func() {})
+				return err
+			},
+			Expected: "header parameter color: unsupported value type func()",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Encode()
+			if err == nil || err.Error() != tc.Expected {
+				t.Errorf("expected error: %s, got: %v", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func getTestName(param rest.RequestParameter, value any) string {
+	explode := "default"
+	if param.Explode != nil && *param.Explode {
+		explode = "explode"
+	} else if param.Explode != nil {
+		explode = "no_explode"
+	}
+	kind := "primitive"
+	switch value.(type) {
+	case nil:
+		kind = "nil"
+	case []any, []int, []string:
+		kind = "array"
+	case map[string]any:
+		kind = "object"
+	}
+	return strings.Join([]string{string(param.Style), explode, kind}, "/")
+}
+
+func toPtr[V any](value V) *V {
+	return &value
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // NDCRestSettings represent global settings of the REST API, including base URL, headers, etc...
@@ -38,6 +41,16 @@ func (j *NDCRestSettings) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *NDCRestSettings) UnmarshalYAML(node *yaml.Node) error {
+	rawBytes, err := yamlNodeToJSON(node)
+	if err != nil {
+		return err
+	}
+
+	return j.UnmarshalJSON(rawBytes)
+}
+
 // Validate if the current instance is valid
 func (rs NDCRestSettings) Validate() error {
 	for _, server := range rs.Servers {
@@ -60,6 +73,22 @@ func (rs NDCRestSettings) Validate() error {
 	return nil
 }
 
+// get names of security schemes in global and server settings
+func (rs NDCRestSettings) securitySchemeNames() []string {
+	var results []string
+	for key := range rs.SecuritySchemes {
+		results = append(results, key)
+	}
+	for _, server := range rs.Servers {
+		for key := range server.SecuritySchemes {
+			if !slices.Contains(results, key) {
+				results = append(results, key)
+			}
+		}
+	}
+	return results
+}
+
 // RetryPolicySetting represents retry policy settings
 type RetryPolicySetting struct {
 	// Number of retry times
@@ -0,0 +1,559 @@
+// Package validate checks decoded arguments of NDC REST operations against type schemas of the request.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// RootPath is the JSON path of the arguments object
+const RootPath = "$"
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Error represents a validation failure of the value at a JSON path, e.g. $.body.address.zip
+type Error struct {
+	Path    string
+	Message string
+}
+
+// Error implements the error interface
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validator validates decoded arguments against type schemas of requests.
+// Named types, e.g. {"type": "Pet"}, are resolved from object and scalar types of the NDC REST schema
+type Validator struct {
+	schema   *rest.NDCRestSchema
+	lock     sync.RWMutex
+	patterns map[string]compiledPattern
+}
+
+type compiledPattern struct {
+	regexp *regexp.Regexp
+	err    error
+}
+
+// NewValidator creates a validator of the NDC REST schema. Patterns of type schemas are compiled once.
+// Returns an error if any pattern isn't supported by the regexp engine
+func NewValidator(ndcSchema *rest.NDCRestSchema) (*Validator, error) {
+	validator := &Validator{
+		schema:   ndcSchema,
+		patterns: map[string]compiledPattern{},
+	}
+	if ndcSchema == nil {
+		return validator, nil
+	}
+
+	var errs []error
+	validateRequest := func(kind string, name string, request *rest.Request) {
+		if request == nil {
+			return
+		}
+		for _, param := range request.Parameters {
+			argName := param.ArgumentName
+			if argName == "" {
+				argName = param.Name
+			}
+			if err := validator.compilePatterns(param.Schema, JoinPath(RootPath, argName)); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", kind, name, err))
+			}
+		}
+		if request.RequestBody != nil {
+			if err := validator.compilePatterns(request.RequestBody.Schema, JoinPath(RootPath, "body")); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", kind, name, err))
+			}
+		}
+	}
+	for _, collection := range ndcSchema.Collections {
+		validateRequest("collection", collection.Name, collection.Request)
+	}
+	for _, fn := range ndcSchema.Functions {
+		validateRequest("function", fn.Name, fn.Request)
+	}
+	for _, proc := range ndcSchema.Procedures {
+		validateRequest("procedure", proc.Name, proc.Request)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return validator, nil
+}
+
+// ValidateArguments validates decoded arguments against type schemas of parameters and the request body.
+// All failures are joined in the result, each one is an Error with the path of the invalid value
+func (v *Validator) ValidateArguments(request *rest.Request, arguments map[string]any) error {
+	if request == nil {
+		return nil
+	}
+	var errs []error
+	var paginationParams []string
+	if request.Pagination != nil {
+		paginationParams = request.Pagination.ParameterNames()
+	}
+	for _, param := range request.Parameters {
+		// pagination parameters are evaluated from the limit, offset and order_by of the query request
+		if param.In == rest.InQuery && slices.Contains(paginationParams, param.Name) {
+			continue
+		}
+		argName := param.ArgumentName
+		if argName == "" {
+			argName = param.Name
+		}
+		value, ok := arguments[argName]
+		errs = append(errs, v.validateValue(param.Schema, value, ok, JoinPath(RootPath, argName))...)
+	}
+	if request.RequestBody != nil {
+		value, ok := arguments["body"]
+		errs = append(errs, v.validateValue(request.RequestBody.Schema, value, ok, JoinPath(RootPath, "body"))...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateValue validates the value against the type schema. The path is used as the prefix of error paths
+func (v *Validator) ValidateValue(typeSchema *rest.TypeSchema, value any, path string) error {
+	return errors.Join(v.validateValue(typeSchema, value, true, path)...)
+}
+
+// JoinPath appends the property name to the JSON path
+func JoinPath(path string, name string) string {
+	if identifierRegex.MatchString(name) {
+		return fmt.Sprintf("%s.%s", path, name)
+	}
+	return fmt.Sprintf("%s[%s]", path, strconv.Quote(name))
+}
+
+func (v *Validator) validateValue(typeSchema *rest.TypeSchema, value any, exists bool, path string) []error {
+	if typeSchema == nil {
+		return nil
+	}
+	if isNil(value) {
+		// the server applies the default value if the argument is omitted
+		if typeSchema.Nullable || (!exists && typeSchema.Default != nil) {
+			return nil
+		}
+		return []error{newError(path, "value is required")}
+	}
+
+	var errs []error
+	if len(typeSchema.Enum) > 0 && !slices.ContainsFunc(typeSchema.Enum, func(item any) bool {
+		return equalValues(item, value)
+	}) {
+		errs = append(errs, newError(path, "value %v must be one of %v", formatValue(value), typeSchema.Enum))
+	}
+	if typeSchema.Const != nil && !equalValues(typeSchema.Const, value) {
+		errs = append(errs, newError(path, "value %v must be %v", formatValue(value), typeSchema.Const))
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch {
+	case typeSchema.Type == "array" || typeSchema.Items != nil:
+		return append(errs, v.validateArray(typeSchema, reflectValue, path)...)
+	case len(typeSchema.Properties) > 0 || typeSchema.MaxProperties != nil || typeSchema.MinProperties != nil:
+		return append(errs, v.validateObject(typeSchema, reflectValue, path)...)
+	}
+	if objectType, ok := v.getObjectType(typeSchema.Type); ok {
+		return append(errs, v.validateObjectFields(objectType, reflectValue, nil, path)...)
+	}
+	if scalarType, ok := v.getScalarType(typeSchema.Type); ok {
+		// other keywords can't be checked if the value doesn't match the scalar type
+		scalarErrs := validateScalar(typeSchema.Type, scalarType, value, len(typeSchema.Enum) == 0, path)
+		if len(scalarErrs) > 0 {
+			return append(errs, scalarErrs...)
+		}
+	}
+
+	if str, ok := value.(string); ok {
+		errs = append(errs, v.validateString(typeSchema, str, path)...)
+		// large numbers can be encoded as strings to avoid precision loss
+		if typeSchema.Representation == rest.NumberRepresentationString {
+			if number, err := strconv.ParseFloat(str, 64); err == nil {
+				errs = append(errs, validateNumber(typeSchema, number, path)...)
+			}
+		}
+	} else if number, ok := toFloat64(value); ok {
+		errs = append(errs, validateNumber(typeSchema, number, path)...)
+	}
+
+	return errs
+}
+
+// validate the value against the NDC type of an object field, which doesn't have keywords of the type schema
+func (v *Validator) validateType(fieldType schema.Type, value any, path string) []error {
+	switch ty := fieldType.Interface().(type) {
+	case *schema.NullableType:
+		if isNil(value) {
+			return nil
+		}
+		return v.validateType(ty.UnderlyingType, value, path)
+	case *schema.ArrayType:
+		if isNil(value) {
+			return []error{newError(path, "value is required")}
+		}
+		reflectValue := reflect.ValueOf(value)
+		if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+			return []error{newError(path, "expected an array, got %T", value)}
+		}
+		var errs []error
+		for i := 0; i < reflectValue.Len(); i++ {
+			errs = append(errs, v.validateType(ty.ElementType, reflectValue.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case *schema.NamedType:
+		if isNil(value) {
+			return []error{newError(path, "value is required")}
+		}
+		if objectType, ok := v.getObjectType(ty.Name); ok {
+			return v.validateObjectFields(objectType, reflect.ValueOf(value), nil, path)
+		}
+		if scalarType, ok := v.getScalarType(ty.Name); ok {
+			return validateScalar(ty.Name, scalarType, value, true, path)
+		}
+	}
+	return nil
+}
+
+func (v *Validator) validateString(typeSchema *rest.TypeSchema, value string, path string) []error {
+	var errs []error
+	length := int64(utf8.RuneCountInString(value))
+	if typeSchema.MinLength != nil && length < *typeSchema.MinLength {
+		errs = append(errs, newError(path, "length must be at least %d, got %d", *typeSchema.MinLength, length))
+	}
+	if typeSchema.MaxLength != nil && length > *typeSchema.MaxLength {
+		errs = append(errs, newError(path, "length must be at most %d, got %d", *typeSchema.MaxLength, length))
+	}
+	if typeSchema.Pattern != "" {
+		pattern, err := v.getPattern(typeSchema.Pattern)
+		if err != nil {
+			errs = append(errs, newError(path, "invalid pattern %s: %s", typeSchema.Pattern, err))
+		} else if !pattern.MatchString(value) {
+			errs = append(errs, newError(path, "value %q does not match pattern %s", value, typeSchema.Pattern))
+		}
+	}
+
+	return errs
+}
+
+func validateNumber(typeSchema *rest.TypeSchema, value float64, path string) []error {
+	var errs []error
+	if typeSchema.Minimum != nil && value < *typeSchema.Minimum {
+		errs = append(errs, newError(path, "value %v must be greater than or equal to %v", value, *typeSchema.Minimum))
+	}
+	if typeSchema.Maximum != nil && value > *typeSchema.Maximum {
+		errs = append(errs, newError(path, "value %v must be less than or equal to %v", value, *typeSchema.Maximum))
+	}
+	if typeSchema.ExclusiveMinimum != nil && value <= *typeSchema.ExclusiveMinimum {
+		errs = append(errs, newError(path, "value %v must be greater than %v", value, *typeSchema.ExclusiveMinimum))
+	}
+	if typeSchema.ExclusiveMaximum != nil && value >= *typeSchema.ExclusiveMaximum {
+		errs = append(errs, newError(path, "value %v must be less than %v", value, *typeSchema.ExclusiveMaximum))
+	}
+	if typeSchema.MultipleOf != nil && *typeSchema.MultipleOf > 0 {
+		quotient := value / *typeSchema.MultipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			errs = append(errs, newError(path, "value %v must be a multiple of %v", value, *typeSchema.MultipleOf))
+		}
+	}
+
+	return errs
+}
+
+func (v *Validator) validateArray(typeSchema *rest.TypeSchema, value reflect.Value, path string) []error {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []error{newError(path, "expected an array, got %T", value.Interface())}
+	}
+
+	var errs []error
+	length := int64(value.Len())
+	if typeSchema.MinItems != nil && length < *typeSchema.MinItems {
+		errs = append(errs, newError(path, "must have at least %d items, got %d", *typeSchema.MinItems, length))
+	}
+	if typeSchema.MaxItems != nil && length > *typeSchema.MaxItems {
+		errs = append(errs, newError(path, "must have at most %d items, got %d", *typeSchema.MaxItems, length))
+	}
+
+	items := make([]any, value.Len())
+	for i := range items {
+		items[i] = value.Index(i).Interface()
+	}
+	if typeSchema.UniqueItems {
+		for i := 1; i < len(items); i++ {
+			if slices.ContainsFunc(items[:i], func(item any) bool {
+				return equalValues(item, items[i])
+			}) {
+				errs = append(errs, newError(fmt.Sprintf("%s[%d]", path, i), "items must be unique"))
+			}
+		}
+	}
+	if typeSchema.Items != nil {
+		for i, item := range items {
+			errs = append(errs, v.validateValue(typeSchema.Items, item, true, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return errs
+}
+
+func (v *Validator) validateObject(typeSchema *rest.TypeSchema, value reflect.Value, path string) []error {
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return []error{newError(path, "expected an object, got %T", value.Interface())}
+	}
+
+	var errs []error
+	length := int64(value.Len())
+	if typeSchema.MinProperties != nil && length < *typeSchema.MinProperties {
+		errs = append(errs, newError(path, "must have at least %d properties, got %d", *typeSchema.MinProperties, length))
+	}
+	if typeSchema.MaxProperties != nil && length > *typeSchema.MaxProperties {
+		errs = append(errs, newError(path, "must have at most %d properties, got %d", *typeSchema.MaxProperties, length))
+	}
+
+	keys := make([]string, 0, len(typeSchema.Properties))
+	for key := range typeSchema.Properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		prop := typeSchema.Properties[key]
+		propValue, ok := getMapValue(value, key)
+		errs = append(errs, v.validateValue(&prop, propValue, ok, JoinPath(path, key))...)
+	}
+	// fields of the named object type which don't have type schemas are validated with NDC types
+	if objectType, ok := v.getObjectType(typeSchema.Type); ok {
+		errs = append(errs, v.validateObjectFields(objectType, value, typeSchema.Properties, path)...)
+	}
+
+	return errs
+}
+
+// validate fields of the object type, except fields that are validated by properties of the type schema
+func (v *Validator) validateObjectFields(objectType schema.ObjectType, value reflect.Value, properties map[string]rest.TypeSchema, path string) []error {
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return []error{newError(path, "expected an object, got %T", value.Interface())}
+	}
+
+	keys := make([]string, 0, len(objectType.Fields))
+	for key := range objectType.Fields {
+		if _, ok := properties[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	var errs []error
+	for _, key := range keys {
+		fieldValue, _ := getMapValue(value, key)
+		errs = append(errs, v.validateType(objectType.Fields[key].Type, fieldValue, JoinPath(path, key))...)
+	}
+
+	return errs
+}
+
+func (v *Validator) getObjectType(name string) (schema.ObjectType, bool) {
+	if v.schema == nil || name == "" {
+		return schema.ObjectType{}, false
+	}
+	objectType, ok := v.schema.ObjectTypes[name]
+	return objectType, ok
+}
+
+func (v *Validator) getScalarType(name string) (schema.ScalarType, bool) {
+	if v.schema == nil || name == "" {
+		return schema.ScalarType{}, false
+	}
+	scalarType, ok := v.schema.ScalarTypes[name]
+	return scalarType, ok
+}
+
+// get the compiled pattern from the cache, or compile and cache it
+func (v *Validator) getPattern(pattern string) (*regexp.Regexp, error) {
+	v.lock.RLock()
+	result, ok := v.patterns[pattern]
+	v.lock.RUnlock()
+	if ok {
+		return result.regexp, result.err
+	}
+
+	result.regexp, result.err = regexp.Compile(pattern)
+	v.lock.Lock()
+	v.patterns[pattern] = result
+	v.lock.Unlock()
+	return result.regexp, result.err
+}
+
+// compile patterns of the type schema and its properties and items
+func (v *Validator) compilePatterns(typeSchema *rest.TypeSchema, path string) error {
+	if typeSchema == nil {
+		return nil
+	}
+	var errs []error
+	if typeSchema.Pattern != "" {
+		if _, err := v.getPattern(typeSchema.Pattern); err != nil {
+			errs = append(errs, newError(path, "invalid pattern %s: %s", typeSchema.Pattern, err))
+		}
+	}
+	if typeSchema.Items != nil {
+		errs = append(errs, v.compilePatterns(typeSchema.Items, path+"[*]"))
+	}
+	for key, prop := range typeSchema.Properties {
+		errs = append(errs, v.compilePatterns(&prop, JoinPath(path, key)))
+	}
+	return errors.Join(errs...)
+}
+
+// validate the JSON type and range of the value against the representation of the scalar type.
+// The enum representation is checked if checkEnum is true
+func validateScalar(name string, scalarType schema.ScalarType, value any, checkEnum bool, path string) []error {
+	if scalarType.Representation == nil {
+		return nil
+	}
+	representation, err := scalarType.Representation.Type()
+	if err != nil {
+		return nil
+	}
+	switch representation {
+	case schema.TypeRepresentationTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return []error{newError(path, "expected %s, got %T", name, value)}
+		}
+	case schema.TypeRepresentationTypeString, schema.TypeRepresentationTypeUUID, schema.TypeRepresentationTypeDate,
+		schema.TypeRepresentationTypeTimestamp, schema.TypeRepresentationTypeTimestampTZ, schema.TypeRepresentationTypeBytes:
+		if _, ok := value.(string); !ok {
+			return []error{newError(path, "expected %s, got %T", name, value)}
+		}
+	case schema.TypeRepresentationTypeEnum:
+		enum, err := scalarType.Representation.AsEnum()
+		if err != nil || !checkEnum {
+			return nil
+		}
+		// numeric enums are converted to enum scalars of string values
+		if _, isString := value.(string); !isString {
+			if _, isNumber := toFloat64(value); !isNumber {
+				return []error{newError(path, "expected %s, got %T", name, value)}
+			}
+		}
+		if !slices.Contains(enum.OneOf, fmt.Sprint(value)) {
+			return []error{newError(path, "value %v must be one of %v", formatValue(value), enum.OneOf)}
+		}
+	case schema.TypeRepresentationTypeInt8, schema.TypeRepresentationTypeInt16, schema.TypeRepresentationTypeInt32,
+		schema.TypeRepresentationTypeInt64, schema.TypeRepresentationTypeInteger, schema.TypeRepresentationTypeBigInteger:
+		number, ok := toScalarNumber(representation, value)
+		if !ok {
+			return []error{newError(path, "expected %s, got %T", name, value)}
+		}
+		if number != math.Trunc(number) {
+			return []error{newError(path, "value %v must be an integer", number)}
+		}
+		if bits, ok := integerBits[representation]; ok {
+			limit := math.Pow(2, float64(bits-1))
+			if number < -limit || number >= limit {
+				return []error{newError(path, "value %v is out of the %s range", number, name)}
+			}
+		}
+	case schema.TypeRepresentationTypeFloat32, schema.TypeRepresentationTypeFloat64,
+		schema.TypeRepresentationTypeNumber, schema.TypeRepresentationTypeBigDecimal:
+		if _, ok := toScalarNumber(representation, value); !ok {
+			return []error{newError(path, "expected %s, got %T", name, value)}
+		}
+	}
+	return nil
+}
+
+var integerBits = map[schema.TypeRepresentationType]int{
+	schema.TypeRepresentationTypeInt8:  8,
+	schema.TypeRepresentationTypeInt16: 16,
+	schema.TypeRepresentationTypeInt32: 32,
+	schema.TypeRepresentationTypeInt64: 64,
+}
+
+// convert the value of a numeric scalar. Large numbers can be encoded as strings to avoid precision loss
+func toScalarNumber(representation schema.TypeRepresentationType, value any) (float64, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return toFloat64(value)
+	}
+	switch representation {
+	case schema.TypeRepresentationTypeInt64, schema.TypeRepresentationTypeBigInteger, schema.TypeRepresentationTypeBigDecimal:
+		number, err := strconv.ParseFloat(str, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func getMapValue(value reflect.Value, key string) (any, bool) {
+	item := value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key()))
+	if !item.IsValid() {
+		return nil, false
+	}
+	return item.Interface(), true
+}
+
+func newError(path string, format string, args ...any) error {
+	return Error{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return reflectValue.IsNil()
+	default:
+		return false
+	}
+}
+
+func formatValue(value any) string {
+	if str, ok := value.(string); ok {
+		return strconv.Quote(str)
+	}
+	return fmt.Sprint(value)
+}
+
+// equalValues compares decoded values. Numbers are compared by value regardless of their Go types
+func equalValues(a, b any) bool {
+	if x, ok := toFloat64(a); ok {
+		y, ok := toFloat64(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		result, err := v.Float64()
+		return result, err == nil
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflectValue.Uint()), true
+	default:
+		return 0, false
+	}
+}
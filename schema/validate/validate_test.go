@@ -0,0 +1,337 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+)
+
+func TestValidateArguments(t *testing.T) {
+	validator, err := NewValidator(newTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request rest.Request
+	if err := json.Unmarshal([]byte(`{
+		"url": "/users/{id}",
+		"method": "post",
+		"parameters": [
+			{ "name": "id", "in": "path", "schema": { "type": "Int32", "minimum": 1 } },
+			{ "name": "role", "in": "query", "schema": { "type": "UserRole", "nullable": true, "enum": ["admin", "member"] } },
+			{ "name": "limit", "in": "query", "schema": { "type": "Int32", "default": 10, "maximum": 100, "multipleOf": 5 } },
+			{ "name": "X-Request-ID", "argumentName": "requestId", "in": "header", "schema": { "type": "UUID", "nullable": true, "pattern": "^[a-f0-9-]+$" } },
+			{ "name": "offset", "in": "query", "schema": { "type": "Int32" } }
+		],
+		"pagination": { "style": "offset", "offsetParameter": "offset" },
+		"requestBody": {
+			"contentType": "application/json",
+			"schema": {
+				"type": "object",
+				"maxProperties": 3,
+				"properties": {
+					"name": { "type": "String", "minLength": 2, "maxLength": 5 },
+					"balance": { "type": "Int64", "nullable": true, "representation": "string", "exclusiveMinimum": 0 },
+					"address": {
+						"type": "Address",
+						"nullable": true,
+						"properties": {
+							"zip": { "type": "String", "pattern": "^[0-9]{5}$" },
+							"first line": { "type": "String", "nullable": true, "const": "home" }
+						}
+					},
+					"tags": {
+						"type": "array",
+						"nullable": true,
+						"minItems": 1,
+						"uniqueItems": true,
+						"items": { "type": "String", "maxLength": 3 }
+					}
+				}
+			}
+		}
+	}`), &request); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name      string
+		Arguments map[string]any
+		Expected  []string
+	}{
+		{
+			Name: "valid",
+			Arguments: map[string]any{
+				"id":        1,
+				"role":      "admin",
+				"limit":     float64(20),
+				"requestId": "abc-123",
+				"body": map[string]any{
+					"name":    "Bob",
+					"balance": "100",
+					"address": map[string]any{
+						"zip":        "12345",
+						"first line": "home",
+					},
+				},
+			},
+		},
+		{
+			Name: "json_number",
+			Arguments: map[string]any{
+				"id":    json.Number("2"),
+				"limit": json.Number("5"),
+				"body": map[string]any{
+					"name": "Alice",
+					"tags": []any{"a", "b"},
+				},
+			},
+		},
+		{
+			Name:      "required",
+			Arguments: map[string]any{},
+			Expected: []string{
+				"$.id: value is required",
+				"$.body: value is required",
+			},
+		},
+		{
+			Name: "enum_and_numbers",
+			Arguments: map[string]any{
+				"id":        0,
+				"role":      "owner",
+				"limit":     int64(101),
+				"requestId": "XYZ",
+				"body":      map[string]any{"name": "Bob"},
+			},
+			Expected: []string{
+				"$.id: value 0 must be greater than or equal to 1",
+				`$.role: value "owner" must be one of [admin member]`,
+				"$.limit: value 101 must be less than or equal to 100",
+				"$.limit: value 101 must be a multiple of 5",
+				`$.requestId: value "XYZ" does not match pattern ^[a-f0-9-]+$`,
+			},
+		},
+		{
+			Name: "nested",
+			Arguments: map[string]any{
+				"id": 1,
+				"body": map[string]any{
+					"name":    "B",
+					"balance": "0",
+					"address": map[string]any{
+						"zip":        "1234a",
+						"first line": "work",
+					},
+					"tags":  []string{"abcd", "x", "x"},
+					"extra": true,
+				},
+			},
+			Expected: []string{
+				"$.body: must have at most 3 properties, got 5",
+				`$.body.address["first line"]: value "work" must be home`,
+				`$.body.address.zip: value "1234a" does not match pattern ^[0-9]{5}$`,
+				"$.body.balance: value 0 must be greater than 0",
+				"$.body.name: length must be at least 2, got 1",
+				"$.body.tags[2]: items must be unique",
+				"$.body.tags[0]: length must be at most 3, got 4",
+			},
+		},
+		{
+			Name: "scalar_types",
+			Arguments: map[string]any{
+				"id":    "1",
+				"limit": 2.5,
+				"body": map[string]any{
+					"name":    true,
+					"balance": "abc",
+				},
+			},
+			Expected: []string{
+				"$.id: expected Int32, got string",
+				"$.limit: value 2.5 must be an integer",
+				"$.body.balance: expected Int64, got string",
+				"$.body.name: expected String, got bool",
+			},
+		},
+		{
+			Name: "types",
+			Arguments: map[string]any{
+				"id": 1,
+				"body": map[string]any{
+					"name":    "Bob",
+					"address": "Main street",
+					"tags":    []any{},
+				},
+			},
+			Expected: []string{
+				"$.body.address: expected an object, got string",
+				"$.body.tags: must have at least 1 items, got 0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validator.ValidateArguments(&request, tc.Arguments)
+			var messages []string
+			if err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var validationError Error
+					if !errors.As(e, &validationError) {
+						t.Fatalf("expected validation error, got: %v", e)
+					}
+					messages = append(messages, e.Error())
+				}
+			}
+			if !slices.Equal(tc.Expected, messages) {
+				t.Errorf("not equal\nexpected: %v\ngot     : %v", tc.Expected, messages)
+			}
+		})
+	}
+}
+
+func TestValidateNamedTypes(t *testing.T) {
+	validator, err := NewValidator(newTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := &rest.Request{
+		RequestBody: &rest.RequestBody{
+			Schema: &rest.TypeSchema{Type: "Pet"},
+		},
+	}
+
+	if err := validator.ValidateArguments(request, map[string]any{
+		"body": map[string]any{
+			"id":    json.Number("1"),
+			"name":  "Bob",
+			"tags":  []any{"a"},
+			"owner": map[string]any{"zip": "12345"},
+			"role":  "admin",
+		},
+	}); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+
+	// fields of named object types are resolved from the schema
+	err = validator.ValidateArguments(request, map[string]any{
+		"body": map[string]any{
+			"id":    int64(128),
+			"tags":  []any{"a", 1},
+			"owner": map[string]any{"first line": 1},
+			"role":  "owner",
+			"size":  int64(200),
+		},
+	})
+	expected := []string{
+		"$.body.name: value is required",
+		`$.body.owner["first line"]: expected String, got int`,
+		"$.body.owner.zip: value is required",
+		`$.body.role: value "owner" must be one of [admin member]`,
+		"$.body.size: value 200 is out of the Int8 range",
+		"$.body.tags[1]: expected String, got int",
+	}
+	if err == nil || err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("not equal\nexpected: %v\ngot     : %v", strings.Join(expected, "\n"), err)
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	ndcSchema := newTestSchema(t)
+	ndcSchema.Functions = []*rest.RESTFunctionInfo{
+		{
+			Request: &rest.Request{
+				Parameters: []rest.RequestParameter{
+					{Name: "code", In: rest.InQuery, Schema: &rest.TypeSchema{Type: "String", Pattern: "^[0-9]{3}$"}},
+				},
+				RequestBody: &rest.RequestBody{
+					Schema: &rest.TypeSchema{
+						Type:  "array",
+						Items: &rest.TypeSchema{Type: "String", Pattern: "(a"},
+					},
+				},
+			},
+		},
+	}
+	ndcSchema.Functions[0].Name = "getCodes"
+
+	_, err := NewValidator(ndcSchema)
+	if err == nil || !strings.HasPrefix(err.Error(), "function getCodes: $.body[*]: invalid pattern (a") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// patterns are compiled once and reused
+	ndcSchema.Functions[0].Request.RequestBody = nil
+	validator, err := NewValidator(ndcSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := validator.patterns["^[0-9]{3}$"]; !ok {
+		t.Errorf("expected the compiled pattern, got: %v", validator.patterns)
+	}
+	err = validator.ValidateValue(&rest.TypeSchema{Type: "String", Pattern: "[a-"}, "a", RootPath)
+	if err == nil || !strings.HasPrefix(err.Error(), "$: invalid pattern [a-: ") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	validator, err := NewValidator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeSchema := &rest.TypeSchema{
+		Type:  "array",
+		Items: &rest.TypeSchema{Type: "Float64", Enum: []any{1, 2.5}},
+	}
+	if err := validator.ValidateValue(typeSchema, []any{float64(1), 2.5}, RootPath); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	err = validator.ValidateValue(typeSchema, []any{3}, RootPath)
+	if err == nil || err.Error() != "$[0]: value 3 must be one of [1 2.5]" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func newTestSchema(t *testing.T) *rest.NDCRestSchema {
+	t.Helper()
+	var result rest.NDCRestSchema
+	if err := json.Unmarshal([]byte(`{
+		"collections": [],
+		"functions": [],
+		"procedures": [],
+		"object_types": {
+			"Address": {
+				"fields": {
+					"zip": { "type": { "type": "named", "name": "String" } },
+					"first line": { "type": { "type": "nullable", "underlying_type": { "type": "named", "name": "String" } } }
+				}
+			},
+			"Pet": {
+				"fields": {
+					"id": { "type": { "type": "named", "name": "Int64" } },
+					"name": { "type": { "type": "named", "name": "String" } },
+					"tags": { "type": { "type": "nullable", "underlying_type": { "type": "array", "element_type": { "type": "named", "name": "String" } } } },
+					"owner": { "type": { "type": "nullable", "underlying_type": { "type": "named", "name": "Address" } } },
+					"role": { "type": { "type": "named", "name": "UserRole" } },
+					"size": { "type": { "type": "nullable", "underlying_type": { "type": "named", "name": "Int8" } } }
+				}
+			}
+		},
+		"scalar_types": {
+			"Int8": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "int8" } },
+			"Int32": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "int32" } },
+			"Int64": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "int64" } },
+			"String": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "string" } },
+			"UUID": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "uuid" } },
+			"UserRole": { "aggregate_functions": {}, "comparison_operators": {}, "representation": { "type": "enum", "one_of": ["admin", "member"] } }
+		}
+	}`), &result); err != nil {
+		t.Fatal(err)
+	}
+	return &result
+}
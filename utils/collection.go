@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+)
+
+// CollectionRule declares GET operations which match the filter as collections
+type CollectionRule struct {
+	OperationFilter `yaml:",inline"`
+	// Override the detected pagination settings of matched operations
+	Pagination *schema.PaginationInfo `json:"pagination,omitempty" yaml:"pagination"`
+}
+
+// ValidateCollectionRules validates filters and pagination settings of collection rules
+func ValidateCollectionRules(rules []CollectionRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if err := rule.OperationFilter.Validate(); err != nil {
+			return fmt.Errorf("collections[%d]: %s", i, err)
+		}
+		if rule.Pagination != nil && rule.Pagination.Style != "" && !rule.Pagination.Style.IsValid() {
+			return fmt.Errorf("collections[%d]: invalid pagination style %s", i, rule.Pagination.Style)
+		}
+	}
+	return nil
+}
+
+// FindCollectionRule returns the first collection rule that matches the operation
+func FindCollectionRule(rules []CollectionRule, input OperationFilterInput) *CollectionRule {
+	for i, rule := range rules {
+		if rule.Match(input) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
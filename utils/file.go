@@ -60,6 +60,34 @@ func WriteSchemaFile(outputPath string, content any) error {
 	return os.WriteFile(outputPath, rawBytes, 0664)
 }
 
+// ReadSchemaFile reads and decodes the NDC REST schema from a JSON or YAML file
+func ReadSchemaFile(filePath string) (*schema.NDCRestSchema, error) {
+	rawBytes, err := ReadFileFromPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := UnmarshalSchema(rawBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode NDC REST schema from %s: %s", filePath, err)
+	}
+	return result, nil
+}
+
+// UnmarshalSchema decodes the NDC REST schema from JSON or YAML bytes
+func UnmarshalSchema(input []byte) (*schema.NDCRestSchema, error) {
+	jsonBytes, err := convertMaybeYAMLToJSONBytes(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var result schema.NDCRestSchema
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // ReadFileFromPath read file content from either file path or URL
 func ReadFileFromPath(filePath string) ([]byte, error) {
 	var result []byte
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+)
+
+// OperationFilter represents a rule to match API operations.
+// An operation matches the rule if it satisfies all non-empty conditions
+type OperationFilter struct {
+	// Match operations which have at least one of these tags
+	Tags []string `json:"tags,omitempty" yaml:"tags"`
+	// Match operations whose path matches one of these glob patterns, e.g. /pets/*, /v1/**.
+	// The * wildcard matches any characters except /, and ** matches any characters
+	Paths []string `json:"paths,omitempty" yaml:"paths"`
+	// Match operations by HTTP methods, e.g. get, post
+	Methods []string `json:"methods,omitempty" yaml:"methods"`
+	// Match operations whose operationId matches the regular expression
+	OperationID string `json:"operationId,omitempty" yaml:"operationId"`
+
+	pathRegexps       []*regexp.Regexp
+	operationIDRegexp *regexp.Regexp
+}
+
+// OperationFilterInput represents the information of an API operation to be filtered
+type OperationFilterInput struct {
+	Path        string
+	Method      string
+	OperationID string
+	Tags        []string
+}
+
+// Validate checks if glob and regular expression patterns of the filter are valid, and compiles them for matching
+func (of *OperationFilter) Validate() error {
+	if len(of.Tags) == 0 && len(of.Paths) == 0 && len(of.Methods) == 0 && of.OperationID == "" {
+		return errors.New("require at least one of tags, paths, methods or operationId")
+	}
+	pathRegexps := make([]*regexp.Regexp, len(of.Paths))
+	for i, pattern := range of.Paths {
+		re, err := compilePathGlob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid path pattern %s: %s", pattern, err)
+		}
+		pathRegexps[i] = re
+	}
+	of.pathRegexps = pathRegexps
+	if of.OperationID != "" {
+		re, err := regexp.Compile(of.OperationID)
+		if err != nil {
+			return fmt.Errorf("invalid operationId pattern %s: %s", of.OperationID, err)
+		}
+		of.operationIDRegexp = re
+	}
+	return nil
+}
+
+// Match checks if the operation satisfies all conditions of the filter.
+// Patterns are compiled on every call if the filter isn't validated
+func (of OperationFilter) Match(input OperationFilterInput) bool {
+	if len(of.Tags) > 0 && !slices.ContainsFunc(input.Tags, func(tag string) bool {
+		return slices.Contains(of.Tags, tag)
+	}) {
+		return false
+	}
+	if len(of.Methods) > 0 && !slices.ContainsFunc(of.Methods, func(method string) bool {
+		return strings.EqualFold(method, input.Method)
+	}) {
+		return false
+	}
+	if len(of.Paths) > 0 {
+		pathRegexps := of.pathRegexps
+		if len(pathRegexps) != len(of.Paths) {
+			pathRegexps = nil
+			for _, pattern := range of.Paths {
+				if re, err := compilePathGlob(pattern); err == nil {
+					pathRegexps = append(pathRegexps, re)
+				}
+			}
+		}
+		if !slices.ContainsFunc(pathRegexps, func(re *regexp.Regexp) bool {
+			return re.MatchString(input.Path)
+		}) {
+			return false
+		}
+	}
+	if of.OperationID != "" {
+		re := of.operationIDRegexp
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(of.OperationID); err != nil {
+				return false
+			}
+		}
+		if !re.MatchString(input.OperationID) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateOperationFilters validates include and exclude filter rules
+func ValidateOperationFilters(include []OperationFilter, exclude []OperationFilter) error {
+	for i := range include {
+		if err := include[i].Validate(); err != nil {
+			return fmt.Errorf("include[%d]: %s", i, err)
+		}
+	}
+	for i := range exclude {
+		if err := exclude[i].Validate(); err != nil {
+			return fmt.Errorf("exclude[%d]: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// IsOperationIncluded checks if the operation matches any include rule and doesn't match any exclude rule.
+// All operations are included if there is no include rule
+func IsOperationIncluded(include []OperationFilter, exclude []OperationFilter, input OperationFilterInput) bool {
+	if len(include) > 0 && !slices.ContainsFunc(include, func(filter OperationFilter) bool {
+		return filter.Match(input)
+	}) {
+		return false
+	}
+	return !slices.ContainsFunc(exclude, func(filter OperationFilter) bool {
+		return filter.Match(input)
+	})
+}
+
+// compilePathGlob converts the path glob pattern to a regular expression
+func compilePathGlob(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteRune('^')
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteRune('$')
+	return regexp.Compile(sb.String())
+}
+
+// OperationKindRule overrides the NDC operation kind of API operations which match the filter
+type OperationKindRule struct {
+	OperationFilter `yaml:",inline"`
+	// The operation kind of matched operations
+	Kind schema.OperationKind `json:"kind" yaml:"kind"`
+}
+
+// ValidateOperationKindRules validates filters and kinds of operation kind rules
+func ValidateOperationKindRules(rules []OperationKindRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if err := rule.OperationFilter.Validate(); err != nil {
+			return fmt.Errorf("operationKinds[%d]: %s", i, err)
+		}
+		if !rule.Kind.IsValid() {
+			return fmt.Errorf("operationKinds[%d]: invalid kind <%s>, expected function or procedure", i, rule.Kind)
+		}
+	}
+	return nil
+}
+
+// FindOperationKind returns the kind of the first rule that matches the operation.
+// Returns an empty string if there is no matched rule
+func FindOperationKind(rules []OperationKindRule, input OperationFilterInput) schema.OperationKind {
+	for _, rule := range rules {
+		if rule.Match(input) {
+			return rule.Kind
+		}
+	}
+	return ""
+}
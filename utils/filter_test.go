@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+)
+
+func TestIsOperationIncluded(t *testing.T) {
+	input := OperationFilterInput{
+		Path:        "/v1/pets/{petId}",
+		Method:      "get",
+		OperationID: "getPetById",
+		Tags:        []string{"pet"},
+	}
+
+	testCases := []struct {
+		Name     string
+		Include  []OperationFilter
+		Exclude  []OperationFilter
+		Expected bool
+	}{
+		{
+			Name:     "no_rule",
+			Expected: true,
+		},
+		{
+			Name:     "include_tag",
+			Include:  []OperationFilter{{Tags: []string{"store", "pet"}}},
+			Expected: true,
+		},
+		{
+			Name:     "include_tag_not_match",
+			Include:  []OperationFilter{{Tags: []string{"store"}}},
+			Expected: false,
+		},
+		{
+			Name:     "include_single_star",
+			Include:  []OperationFilter{{Paths: []string{"/v1/pets/*"}}},
+			Expected: true,
+		},
+		{
+			Name:     "include_single_star_not_match",
+			Include:  []OperationFilter{{Paths: []string{"/v1/*"}}},
+			Expected: false,
+		},
+		{
+			Name:     "include_double_star",
+			Include:  []OperationFilter{{Paths: []string{"/v1/**"}}},
+			Expected: true,
+		},
+		{
+			Name:     "include_question_mark",
+			Include:  []OperationFilter{{Paths: []string{"/v?/pets/{petId}"}}},
+			Expected: true,
+		},
+		{
+			Name:     "include_any_rule",
+			Include:  []OperationFilter{{Tags: []string{"store"}}, {OperationID: "^getPet"}},
+			Expected: true,
+		},
+		{
+			Name:     "include_all_conditions",
+			Include:  []OperationFilter{{Tags: []string{"pet"}, Methods: []string{"post"}}},
+			Expected: false,
+		},
+		{
+			Name:     "exclude_method_case_insensitive",
+			Exclude:  []OperationFilter{{Methods: []string{"GET"}}},
+			Expected: false,
+		},
+		{
+			Name:     "include_and_exclude",
+			Include:  []OperationFilter{{Tags: []string{"pet"}}},
+			Exclude:  []OperationFilter{{OperationID: "ById$"}},
+			Expected: false,
+		},
+		{
+			Name:     "exclude_not_match",
+			Exclude:  []OperationFilter{{OperationID: "^delete"}},
+			Expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result := IsOperationIncluded(tc.Include, tc.Exclude, input)
+			if result != tc.Expected {
+				t.Fatalf("expected: %t, got: %t", tc.Expected, result)
+			}
+
+			// patterns are compiled once by the validation
+			if err := ValidateOperationFilters(tc.Include, tc.Exclude); err != nil {
+				t.Fatal(err)
+			}
+			for _, filter := range append(slices.Clone(tc.Include), tc.Exclude...) {
+				if len(filter.pathRegexps) != len(filter.Paths) || (filter.OperationID != "" && filter.operationIDRegexp == nil) {
+					t.Fatalf("expected compiled patterns of filter %+v", filter)
+				}
+			}
+			result = IsOperationIncluded(tc.Include, tc.Exclude, input)
+			if result != tc.Expected {
+				t.Fatalf("expected: %t, got: %t", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateOperationFilters(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Include  []OperationFilter
+		Exclude  []OperationFilter
+		ErrorMsg string
+	}{
+		{
+			Name:    "valid",
+			Include: []OperationFilter{{Paths: []string{"/pets/**"}}},
+			Exclude: []OperationFilter{{OperationID: "^delete"}},
+		},
+		{
+			Name:     "empty_rule",
+			Include:  []OperationFilter{{}},
+			ErrorMsg: "include[0]: require at least one of tags, paths, methods or operationId",
+		},
+		{
+			Name:     "invalid_regex",
+			Exclude:  []OperationFilter{{Methods: []string{"get"}}, {OperationID: "(foo"}},
+			ErrorMsg: "exclude[1]: invalid operationId pattern (foo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ValidateOperationFilters(tc.Include, tc.Exclude)
+			if tc.ErrorMsg == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.ErrorMsg) {
+				t.Fatalf("expected error: %s, got: %v", tc.ErrorMsg, err)
+			}
+		})
+	}
+}
+
+func TestFindOperationKind(t *testing.T) {
+	rules := []OperationKindRule{
+		{OperationFilter: OperationFilter{Paths: []string{"/search"}, Methods: []string{"post"}}, Kind: schema.OperationFunction},
+		{OperationFilter: OperationFilter{Methods: []string{"post"}}, Kind: schema.OperationProcedure},
+	}
+	assertKind := func(expected schema.OperationKind, input OperationFilterInput) {
+		t.Helper()
+		if got := FindOperationKind(rules, input); got != expected {
+			t.Fatalf("expected: %s, got: %s", expected, got)
+		}
+	}
+	assertKind(schema.OperationFunction, OperationFilterInput{Path: "/search", Method: "post"})
+	assertKind(schema.OperationProcedure, OperationFilterInput{Path: "/pets", Method: "post"})
+	assertKind("", OperationFilterInput{Path: "/search", Method: "get"})
+
+	if err := ValidateOperationKindRules([]OperationKindRule{{OperationFilter: OperationFilter{Methods: []string{"post"}}, Kind: "query"}}); err == nil || !strings.Contains(err.Error(), "operationKinds[0]: invalid kind <query>") {
+		t.Fatalf("expected invalid kind error, got: %v", err)
+	}
+}
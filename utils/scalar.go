@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	rest "github.com/hasura/ndc-rest-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// ScalarMapping maps OpenAPI schemas which match the type, format or extension to a custom scalar type
+type ScalarMapping struct {
+	// The OpenAPI type of matched schemas, e.g. string. Match any type if empty
+	Type string `json:"type,omitempty" yaml:"type,omitempty" jsonschema:"enum=string,enum=integer,enum=number,enum=boolean"`
+	// The format of matched schemas, e.g. money. Match any format if empty
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// The specification extension that matched schemas must have, e.g. x-scalar-type
+	Extension string `json:"extension,omitempty" yaml:"extension,omitempty"`
+	// The value of the extension. Match any value if empty
+	ExtensionValue string `json:"extensionValue,omitempty" yaml:"extensionValue,omitempty"`
+	// The name of the scalar type, e.g. Money
+	Scalar string `json:"scalar" yaml:"scalar"`
+	// The NDC type representation of the scalar type. Inferred from the OpenAPI type if empty
+	Representation schema.TypeRepresentationType `json:"representation,omitempty" yaml:"representation,omitempty"`
+}
+
+// Match checks if the schema matches the mapping. The getExtension function returns the value of the extension of the schema
+func (sm ScalarMapping) Match(typeName string, format string, getExtension func(name string) (string, bool)) bool {
+	if sm.Type != "" && sm.Type != typeName {
+		return false
+	}
+	if sm.Format != "" && sm.Format != format {
+		return false
+	}
+	if sm.Extension == "" {
+		return true
+	}
+	if getExtension == nil {
+		return false
+	}
+	value, ok := getExtension(sm.Extension)
+	return ok && (sm.ExtensionValue == "" || sm.ExtensionValue == value)
+}
+
+// GetRepresentation returns the representation of the scalar type. If empty, the representation is inferred from the OpenAPI type of the matched schema
+func (sm ScalarMapping) GetRepresentation(typeName string) schema.TypeRepresentationType {
+	if sm.Representation != "" {
+		return sm.Representation
+	}
+	switch typeName {
+	case "boolean":
+		return schema.TypeRepresentationTypeBoolean
+	case "integer", "long":
+		return schema.TypeRepresentationTypeInt32
+	case "number":
+		return schema.TypeRepresentationTypeFloat64
+	case "string":
+		return schema.TypeRepresentationTypeString
+	default:
+		return schema.TypeRepresentationTypeJSON
+	}
+}
+
+// ValidateScalarMappings validates conditions and representations of scalar mappings.
+// Built-in scalar names can't be overridden and mappings of the same scalar must have the same representation
+func ValidateScalarMappings(mappings []ScalarMapping) error {
+	for i, mapping := range mappings {
+		if mapping.Scalar == "" {
+			return fmt.Errorf("scalarMappings[%d]: scalar name is required", i)
+		}
+		if rest.IsDefaultScalar(mapping.Scalar) {
+			return fmt.Errorf("scalarMappings[%d]: scalar %s conflicts with the built-in scalar type", i, mapping.Scalar)
+		}
+		if mapping.Type == "" && mapping.Format == "" && mapping.Extension == "" {
+			return fmt.Errorf("scalarMappings[%d]: require at least one of type, format or extension", i)
+		}
+		if mapping.Extension != "" && !strings.HasPrefix(mapping.Extension, "x-") {
+			return fmt.Errorf("scalarMappings[%d]: extension %s must start with x-", i, mapping.Extension)
+		}
+		if mapping.Representation != "" && (!mapping.Representation.IsValid() || mapping.Representation == schema.TypeRepresentationTypeEnum) {
+			return fmt.Errorf("scalarMappings[%d]: invalid representation %s", i, mapping.Representation)
+		}
+		// the representation is unknown until a schema is matched if both type and representation are empty
+		if mapping.Type == "" && mapping.Representation == "" {
+			continue
+		}
+		representation := mapping.GetRepresentation(mapping.Type)
+		for j, prev := range mappings[:i] {
+			if prev.Scalar != mapping.Scalar || (prev.Type == "" && prev.Representation == "") {
+				continue
+			}
+			if prevRepresentation := prev.GetRepresentation(prev.Type); prevRepresentation != representation {
+				return fmt.Errorf("scalarMappings[%d]: representation %s of scalar %s conflicts with representation %s of scalarMappings[%d]", i, representation, mapping.Scalar, prevRepresentation, j)
+			}
+		}
+	}
+	return nil
+}
+
+// FindScalarMapping returns the first scalar mapping that matches the schema
+func FindScalarMapping(mappings []ScalarMapping, typeName string, format string, getExtension func(name string) (string, bool)) *ScalarMapping {
+	for i, mapping := range mappings {
+		if mapping.Match(typeName, format, getExtension) {
+			return &mappings[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+func TestFindScalarMapping(t *testing.T) {
+	mappings := []ScalarMapping{
+		{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeBigDecimal},
+		{Type: "string", Extension: "x-scalar-type", ExtensionValue: "snowflake", Scalar: "SnowflakeID"},
+		{Type: "integer", Extension: "x-timestamp", Scalar: "Epoch"},
+	}
+	extensions := map[string]string{
+		"x-scalar-type": "snowflake",
+		"x-timestamp":   "ms",
+	}
+	getExtension := func(name string) (string, bool) {
+		value, ok := extensions[name]
+		return value, ok
+	}
+
+	testCases := []struct {
+		Name         string
+		Type         string
+		Format       string
+		GetExtension func(name string) (string, bool)
+		Expected     string
+	}{
+		{Name: "format", Type: "number", Format: "money", Expected: "Money"},
+		{Name: "format_any_type", Type: "string", Format: "money", Expected: "Money"},
+		{Name: "extension_value", Type: "string", GetExtension: getExtension, Expected: "SnowflakeID"},
+		{Name: "extension_type_not_match", Type: "number", GetExtension: getExtension},
+		{Name: "extension_any_value", Type: "integer", GetExtension: getExtension, Expected: "Epoch"},
+		{Name: "extension_not_found", Type: "integer"},
+		{Name: "no_match", Type: "string", Format: "uuid"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result := FindScalarMapping(mappings, tc.Type, tc.Format, tc.GetExtension)
+			if tc.Expected == "" {
+				if result != nil {
+					t.Fatalf("expected no mapping, got: %s", result.Scalar)
+				}
+				return
+			}
+			if result == nil || result.Scalar != tc.Expected {
+				t.Fatalf("expected scalar %s, got: %v", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateScalarMappings(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Mappings []ScalarMapping
+		ErrorMsg string
+	}{
+		{
+			Name:     "valid",
+			Mappings: []ScalarMapping{{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeBigDecimal}},
+		},
+		{
+			Name:     "empty_scalar",
+			Mappings: []ScalarMapping{{Format: "money"}},
+			ErrorMsg: "scalarMappings[0]: scalar name is required",
+		},
+		{
+			Name:     "no_condition",
+			Mappings: []ScalarMapping{{Scalar: "Money"}},
+			ErrorMsg: "scalarMappings[0]: require at least one of type, format or extension",
+		},
+		{
+			Name:     "invalid_extension",
+			Mappings: []ScalarMapping{{Format: "money", Scalar: "Money"}, {Extension: "scalar-type", Scalar: "Custom"}},
+			ErrorMsg: "scalarMappings[1]: extension scalar-type must start with x-",
+		},
+		{
+			Name:     "invalid_representation",
+			Mappings: []ScalarMapping{{Format: "money", Scalar: "Money", Representation: "money"}},
+			ErrorMsg: "scalarMappings[0]: invalid representation money",
+		},
+		{
+			Name:     "enum_representation",
+			Mappings: []ScalarMapping{{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeEnum}},
+			ErrorMsg: "scalarMappings[0]: invalid representation enum",
+		},
+		{
+			Name:     "built_in_scalar",
+			Mappings: []ScalarMapping{{Format: "money", Scalar: "String"}},
+			ErrorMsg: "scalarMappings[0]: scalar String conflicts with the built-in scalar type",
+		},
+		{
+			Name: "same_representation",
+			Mappings: []ScalarMapping{
+				{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeString},
+				{Type: "string", Extension: "x-money", Scalar: "Money"},
+				{Extension: "x-currency", Scalar: "Money"},
+			},
+		},
+		{
+			Name: "conflicted_representation",
+			Mappings: []ScalarMapping{
+				{Format: "money", Scalar: "Money", Representation: schema.TypeRepresentationTypeBigDecimal},
+				{Type: "number", Extension: "x-money", Scalar: "Money"},
+			},
+			ErrorMsg: "scalarMappings[1]: representation float64 of scalar Money conflicts with representation bigdecimal of scalarMappings[0]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ValidateScalarMappings(tc.Mappings)
+			if tc.ErrorMsg == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.ErrorMsg) {
+				t.Fatalf("expected error %s, got: %v", tc.ErrorMsg, err)
+			}
+		})
+	}
+}
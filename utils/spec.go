@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// the root keys of the document that are used to detect the API specification
+type specDocumentRoot struct {
+	// versions are decoded as nodes to keep the raw value, e.g. an unquoted 3.0 isn't formatted as 3
+	Swagger     yaml.Node `yaml:"swagger"`
+	OpenAPI     yaml.Node `yaml:"openapi"`
+	AsyncAPI    any       `yaml:"asyncapi"`
+	Settings    any       `yaml:"settings"`
+	Functions   any       `yaml:"functions"`
+	Procedures  any       `yaml:"procedures"`
+	ObjectTypes any       `yaml:"object_types"`
+	ScalarTypes any       `yaml:"scalar_types"`
+}
+
+// DetectSchemaSpec detects the API specification of a JSON or YAML document from its root keys
+func DetectSchemaSpec(input []byte) (schema.SchemaSpecType, error) {
+	if len(strings.TrimSpace(string(input))) == 0 {
+		return "", errors.New("unable to detect the spec of an empty document")
+	}
+
+	var root specDocumentRoot
+	if err := yaml.Unmarshal(input, &root); err != nil {
+		return "", fmt.Errorf("unable to detect the spec, the input is not in either yaml or json format: %s", err)
+	}
+
+	switch {
+	case !root.Swagger.IsZero():
+		version := root.Swagger.Value
+		if version != "2.0" && version != "2" {
+			return "", fmt.Errorf("unsupported Swagger version %s, expected 2.0", version)
+		}
+		return schema.OAS2Spec, nil
+	case !root.OpenAPI.IsZero():
+		version := root.OpenAPI.Value
+		if !strings.HasPrefix(version, "3.") {
+			return "", fmt.Errorf("unsupported OpenAPI version %s, expected 3.x", version)
+		}
+		return schema.OAS3Spec, nil
+	case root.AsyncAPI != nil:
+		return "", fmt.Errorf("AsyncAPI %v documents are not supported, expected OpenAPI 2.0, OpenAPI 3.x or NDC REST schema", root.AsyncAPI)
+	case root.Settings != nil || root.Functions != nil || root.Procedures != nil:
+		return schema.NDCSpec, nil
+	case root.ObjectTypes != nil || root.ScalarTypes != nil:
+		return schema.NDCSpec, nil
+	default:
+		return "", errors.New("unable to detect the spec of the document, expected OpenAPI 2.0, OpenAPI 3.x or NDC REST schema. Please set the spec explicitly")
+	}
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-rest-schema/schema"
+)
+
+func TestDetectSchemaSpec(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Input    string
+		Expected schema.SchemaSpecType
+		ErrorMsg string
+	}{
+		{
+			Name:     "swagger_json",
+			Input:    `{"swagger": "2.0", "info": {"title": "test"}, "paths": {}}`,
+			Expected: schema.OAS2Spec,
+		},
+		{
+			Name:     "swagger_yaml_unquoted",
+			Input:    "swagger: 2.0\npaths: {}",
+			Expected: schema.OAS2Spec,
+		},
+		{
+			Name:     "openapi_3_0",
+			Input:    "openapi: 3.0.3\npaths: {}",
+			Expected: schema.OAS3Spec,
+		},
+		{
+			Name:     "openapi_yaml_unquoted",
+			Input:    "openapi: 3.0\npaths: {}",
+			Expected: schema.OAS3Spec,
+		},
+		{
+			Name:     "openapi_3_1",
+			Input:    `{"openapi": "3.1.0", "paths": {}}`,
+			Expected: schema.OAS3Spec,
+		},
+		{
+			Name:     "ndc",
+			Input:    `{"settings": {"servers": [{"url": "http://localhost"}]}, "functions": [], "procedures": []}`,
+			Expected: schema.NDCSpec,
+		},
+		{
+			Name:     "ndc_types_only",
+			Input:    "object_types: {}\nscalar_types: {}",
+			Expected: schema.NDCSpec,
+		},
+		{
+			Name:     "unsupported_openapi_version",
+			Input:    "openapi: 4.0.0",
+			ErrorMsg: "unsupported OpenAPI version 4.0.0, expected 3.x",
+		},
+		{
+			Name:     "asyncapi",
+			Input:    "asyncapi: 2.6.0\nchannels: {}",
+			ErrorMsg: "AsyncAPI 2.6.0 documents are not supported",
+		},
+		{
+			Name:     "unknown",
+			Input:    `{"foo": "bar"}`,
+			ErrorMsg: "unable to detect the spec of the document",
+		},
+		{
+			Name:     "empty",
+			Input:    " ",
+			ErrorMsg: "unable to detect the spec of an empty document",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := DetectSchemaSpec([]byte(tc.Input))
+			if tc.ErrorMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ErrorMsg) {
+					t.Fatalf("expected error: %s, got: %v", tc.ErrorMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+			if result != tc.Expected {
+				t.Fatalf("expected spec: %s, got: %s", tc.Expected, result)
+			}
+		})
+	}
+}